@@ -3,6 +3,7 @@ package pack
 import (
 	"context"
 	"fmt"
+	"io"
 	"math/rand"
 	"net/url"
 	"os"
@@ -42,7 +43,7 @@ type BuildOptions struct {
 	AppPath            string              // defaults to current working directory
 	RunImage           string              // defaults to the best mirror from the builder metadata or AdditionalMirrors
 	AdditionalMirrors  map[string][]string // only considered if RunImage is not provided
-	Env                map[string]string
+	Env                map[string]string   // injected into the ephemeral builder image, so it is available to the detect and build phases
 	Publish            bool
 	NoPull             bool
 	ClearCache         bool
@@ -320,7 +321,29 @@ func (c *Client) processAppPath(appPath string) (string, error) {
 		}
 
 		if !isZip {
-			return "", errors.New("app path must be a directory or zip")
+			if _, err := fh.Seek(0, io.SeekStart); err != nil {
+				return "", errors.Wrap(err, "seek file")
+			}
+
+			isGZip, err := archive.IsGZip(fh)
+			if err != nil {
+				return "", errors.Wrap(err, "check gzip")
+			}
+
+			if !isGZip {
+				if _, err := fh.Seek(0, io.SeekStart); err != nil {
+					return "", errors.Wrap(err, "seek file")
+				}
+
+				isTar, err := archive.IsTar(fh)
+				if err != nil {
+					return "", errors.Wrap(err, "check tar")
+				}
+
+				if !isTar {
+					return "", errors.New("app path must be a directory, zip, or tar")
+				}
+			}
 		}
 	}
 
@@ -356,45 +379,45 @@ func (c *Client) processProxyConfig(config *ProxyConfig) ProxyConfig {
 //
 // Visual examples:
 //
-// 	BUILDER ORDER
-// 	----------
-//  - group:
-//		- A
-//		- B
-//  - group:
-//		- A
+//		BUILDER ORDER
+//		----------
+//	 - group:
+//			- A
+//			- B
+//	 - group:
+//			- A
 //
-//	WITH DECLARED: "from=builder", X
-// 	----------
-// 	- group:
-//		- A
-//		- B
-//		- X
-// 	 - group:
-//		- A
-//		- X
+//		WITH DECLARED: "from=builder", X
+//		----------
+//		- group:
+//			- A
+//			- B
+//			- X
+//		 - group:
+//			- A
+//			- X
 //
-//	WITH DECLARED: X, "from=builder", Y
-// 	----------
-// 	- group:
-//		- X
-//		- A
-//		- B
-//      - Y
-// 	- group:
-//		- X
-//		- A
-//      - Y
+//		WITH DECLARED: X, "from=builder", Y
+//		----------
+//		- group:
+//			- X
+//			- A
+//			- B
+//	     - Y
+//		- group:
+//			- X
+//			- A
+//	     - Y
 //
-//	WITH DECLARED: X
-// 	----------
-//	- group:
-//		- X
+//		WITH DECLARED: X
+//		----------
+//		- group:
+//			- X
 //
-//	WITH DECLARED: A
-// 	----------
-// 	- group:
-//		- A
+//		WITH DECLARED: A
+//		----------
+//		- group:
+//			- A
 func (c *Client) processBuildpacks(ctx context.Context, builderBPs []dist.BuildpackInfo, builderOrder dist.Order, declaredBPs []string, noPull bool, publish bool) (fetchedBPs []dist.Buildpack, order dist.Order, err error) {
 	order = dist.Order{{Group: []dist.BuildpackRef{}}}
 	for _, bp := range declaredBPs {
@@ -452,7 +475,7 @@ func (c *Client) processBuildpacks(ctx context.Context, builderBPs []dist.Buildp
 					return fetchedBPs, order, errors.Wrapf(err, "extracting buildpacks from %s", style.Symbol(bp))
 				}
 			} else {
-				mainBP, err = dist.BuildpackFromRootBlob(blob)
+				mainBP, err = dist.BuildpackFromRootBlob(blob, dist.WithLogger(c.logger))
 				if err != nil {
 					return fetchedBPs, order, errors.Wrapf(err, "creating buildpack from %s", style.Symbol(bp))
 				}
@@ -564,7 +587,30 @@ func buildPlatformVolumes(volumes []string) ([]string, error) {
 
 		// Use path.Join instead of filepath.Join because we assume the container OS is linux but the host may be windows
 		dest := path.Join("/platform", volume.Destination)
-		platformVolumes[i] = fmt.Sprintf("%v:%v:ro", volume.Spec.Source, dest)
+
+		// volume.RW defaults to true when no mode is given, so it can't be used to preserve the pre-existing
+		// read-only default; only switch to rw when the caller explicitly appends :rw.
+		mode := "ro"
+		if explicitVolumeMode(v) == "rw" {
+			mode = "rw"
+		}
+		platformVolumes[i] = fmt.Sprintf("%v:%v:%v", volume.Spec.Source, dest, mode)
 	}
 	return platformVolumes, nil
 }
+
+// explicitVolumeMode returns the trailing :ro or :rw mode from a Docker-style host:container[:mode]
+// volume spec, or "" if the caller didn't specify one.
+func explicitVolumeMode(raw string) string {
+	parts := strings.SplitN(raw, ":", 3)
+	if len(parts) != 3 {
+		return ""
+	}
+
+	switch parts[2] {
+	case "ro", "rw":
+		return parts[2]
+	default:
+		return ""
+	}
+}
@@ -1559,7 +1559,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					h.SkipIf(t, runtime.GOOS == "windows", "Skipped on windows")
 				})
 
-				it("prepends /platform to the mount paths", func() {
+				it("prepends /platform to the mount paths and defaults to read-only", func() {
 					subject.Build(context.TODO(), BuildOptions{
 						Image:   "some/app",
 						Builder: builderName,
@@ -1574,6 +1574,34 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					h.AssertEq(t, fakeLifecycle.Opts.Volumes, expected)
 				})
 
+				it("honors an explicit :ro mode", func() {
+					subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: builderName,
+						ContainerConfig: ContainerConfig{
+							Volumes: []string{"/a:/x:ro"},
+						},
+					})
+					expected := []string{
+						fmt.Sprintf("/a:%v:ro", filepath.Join("/platform", "x")),
+					}
+					h.AssertEq(t, fakeLifecycle.Opts.Volumes, expected)
+				})
+
+				it("honors an explicit :rw mode", func() {
+					subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: builderName,
+						ContainerConfig: ContainerConfig{
+							Volumes: []string{"/a:/x:rw"},
+						},
+					})
+					expected := []string{
+						fmt.Sprintf("/a:%v:rw", filepath.Join("/platform", "x")),
+					}
+					h.AssertEq(t, fakeLifecycle.Opts.Volumes, expected)
+				})
+
 				when("volume specification is invalid", func() {
 					it("returns an error", func() {
 						err := subject.Build(context.TODO(), BuildOptions{
@@ -1593,7 +1621,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					h.SkipIf(t, runtime.GOOS != "windows", "Skipped on non-windows")
 				})
 
-				it("prepends /platform to the mount paths", func() {
+				it("prepends /platform to the mount paths and defaults to read-only", func() {
 					dir, _ := ioutil.TempDir("", "pack-test-mount")
 					volume := fmt.Sprintf("%v:/x", dir)
 					err := subject.Build(context.TODO(), BuildOptions{
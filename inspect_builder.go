@@ -2,27 +2,15 @@ package pack
 
 import (
 	"context"
-	"strings"
 
 	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/internal/builder"
-	"github.com/buildpacks/pack/internal/dist"
 	"github.com/buildpacks/pack/internal/image"
-	"github.com/buildpacks/pack/internal/style"
 )
 
-type BuilderInfo struct {
-	Description     string
-	Stack           string
-	Mixins          []string
-	RunImage        string
-	RunImageMirrors []string
-	Buildpacks      []dist.BuildpackInfo
-	Order           dist.Order
-	Lifecycle       builder.LifecycleDescriptor
-	CreatedBy       builder.CreatorMetadata
-}
+// BuilderInfo describes a builder image's buildpacks, lifecycle, and run image.
+type BuilderInfo = builder.BuilderInfo
 
 func (c *Client) InspectBuilder(name string, daemon bool) (*BuilderInfo, error) {
 	img, err := c.imageFetcher.Fetch(context.Background(), name, daemon, false)
@@ -33,30 +21,10 @@ func (c *Client) InspectBuilder(name string, daemon bool) (*BuilderInfo, error)
 		return nil, err
 	}
 
-	bldr, err := builder.FromImage(img)
+	info, err := builder.Inspect(img)
 	if err != nil {
-		return nil, errors.Wrapf(err, "invalid builder %s", style.Symbol(name))
-	}
-
-	var commonMixins, buildMixins []string
-	commonMixins = []string{}
-	for _, mixin := range bldr.Mixins() {
-		if strings.HasPrefix(mixin, "build:") {
-			buildMixins = append(buildMixins, mixin)
-		} else {
-			commonMixins = append(commonMixins, mixin)
-		}
+		return nil, err
 	}
 
-	return &BuilderInfo{
-		Description:     bldr.Description(),
-		Stack:           bldr.StackID,
-		Mixins:          append(commonMixins, buildMixins...),
-		RunImage:        bldr.Stack().RunImage.Image,
-		RunImageMirrors: bldr.Stack().RunImage.Mirrors,
-		Buildpacks:      bldr.Buildpacks(),
-		Order:           bldr.Order(),
-		Lifecycle:       bldr.LifecycleDescriptor(),
-		CreatedBy:       bldr.CreatedBy(),
-	}, nil
+	return &info, nil
 }
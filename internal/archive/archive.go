@@ -4,11 +4,13 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"bytes"
+	"compress/gzip"
 	"io"
 	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/docker/docker/pkg/ioutils"
@@ -18,7 +20,24 @@ import (
 var NormalizedDateTime time.Time
 
 func init() {
-	NormalizedDateTime = time.Date(1980, time.January, 1, 0, 0, 1, 0, time.UTC)
+	NormalizedDateTime = normalizedDateTimeFromEnv(os.Getenv("SOURCE_DATE_EPOCH"))
+}
+
+// normalizedDateTimeFromEnv returns the time that SOURCE_DATE_EPOCH (the convention
+// reproducible-build tooling uses to pin timestamps embedded in build output) encodes, or the
+// package's hardcoded normalized time if epoch is empty or isn't a valid Unix timestamp.
+func normalizedDateTimeFromEnv(epoch string) time.Time {
+	defaultTime := time.Date(1980, time.January, 1, 0, 0, 1, 0, time.UTC)
+	if epoch == "" {
+		return defaultTime
+	}
+
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return defaultTime
+	}
+
+	return time.Unix(seconds, 0).UTC()
 }
 
 func ReadDirAsTar(srcDir, basePath string, uid, gid int, mode int64, normalizeModTime bool, fileFilter func(string) bool) io.ReadCloser {
@@ -33,6 +52,15 @@ func ReadZipAsTar(srcPath, basePath string, uid, gid int, mode int64, normalizeM
 	})
 }
 
+// ReadTarAsTar rewrites the tar archive at srcPath (optionally gzip-compressed) so that its
+// entries are rooted under basePath, the same normalization ReadDirAsTar and ReadZipAsTar apply
+// to their sources.
+func ReadTarAsTar(srcPath, basePath string, uid, gid int, mode int64, normalizeModTime bool, fileFilter func(string) bool) io.ReadCloser {
+	return GenerateTar(func(tw *tar.Writer) error {
+		return WriteTarToTar(tw, srcPath, basePath, uid, gid, mode, normalizeModTime, fileFilter)
+	})
+}
+
 // GenerateTar returns a reader to a tar from a generator function. Note that the
 // generator will not fully execute until the reader is fully read from. Any errors
 // returned by the generator will be returned when reading the reader.
@@ -285,6 +313,64 @@ func WriteZipToTar(tw *tar.Writer, srcZip, basePath string, uid, gid int, mode i
 	return nil
 }
 
+// WriteTarToTar copies the entries of the tar archive at srcTar (transparently gunzipping it
+// first if it's gzip-compressed) into tw, rooted under basePath.
+func WriteTarToTar(tw *tar.Writer, srcTar, basePath string, uid, gid int, mode int64, normalizeModTime bool, fileFilter func(string) bool) error {
+	fh, err := os.Open(srcTar)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	isGZip, err := IsGZip(fh)
+	if err != nil {
+		return err
+	}
+	if _, err := fh.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	var r io.Reader = fh
+	if isGZip {
+		gzr, err := gzip.NewReader(fh)
+		if err != nil {
+			return err
+		}
+		defer gzr.Close()
+		r = gzr
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if fileFilter != nil && !fileFilter(header.Name) {
+			continue
+		}
+
+		header.Name = filepath.ToSlash(filepath.Join(basePath, header.Name))
+		finalizeHeader(header, uid, gid, mode, normalizeModTime)
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if header.Typeflag == tar.TypeReg {
+			if _, err := io.Copy(tw, tr); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func finalizeHeader(header *tar.Header, uid, gid int, mode int64, normalizeModTime bool) {
 	NormalizeHeader(header, normalizeModTime)
 	if mode != -1 {
@@ -297,11 +383,11 @@ func finalizeHeader(header *tar.Header, uid, gid int, mode int64, normalizeModTi
 // NormalizeHeader normalizes a tar.Header
 //
 // Normalizes the following:
-// 	- ModTime
-// 	- GID
-// 	- UID
-// 	- User Name
-// 	- Group Name
+//   - ModTime
+//   - GID
+//   - UID
+//   - User Name
+//   - Group Name
 func NormalizeHeader(header *tar.Header, normalizeModTime bool) {
 	if normalizeModTime {
 		header.ModTime = NormalizedDateTime
@@ -323,3 +409,31 @@ func IsZip(file io.Reader) (bool, error) {
 
 	return bytes.Equal(b, []byte("\x50\x4B\x03\x04")), nil
 }
+
+// IsGZip reports whether file begins with the gzip magic number.
+func IsGZip(file io.Reader) (bool, error) {
+	b := make([]byte, 2)
+	_, err := file.Read(b)
+	if err != nil && err != io.EOF {
+		return false, err
+	} else if err == io.EOF {
+		return false, nil
+	}
+
+	return bytes.Equal(b, []byte("\x1f\x8b")), nil
+}
+
+// IsTar reports whether file is an (uncompressed) POSIX tar archive, identified by the "ustar"
+// magic bytes its header block carries at offset 257.
+func IsTar(file io.Reader) (bool, error) {
+	b := make([]byte, 512)
+	n, err := io.ReadFull(file, b)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return false, err
+	}
+	if n < 262 {
+		return false, nil
+	}
+
+	return bytes.Equal(b[257:262], []byte("ustar")), nil
+}
@@ -2,6 +2,9 @@ package archive_test
 
 import (
 	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"math/rand"
@@ -342,6 +345,148 @@ func testArchive(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 	})
+
+	when("#WriteTarToTar", func() {
+		writeSrcTar := func(gzipped bool) string {
+			srcTarBuilder := archive.TarBuilder{}
+			srcTarBuilder.AddFile("some-file.txt", 0644, archive.NormalizedDateTime, []byte("some-content"))
+
+			srcPath := filepath.Join(tmpDir, "src.tar")
+			h.AssertNil(t, srcTarBuilder.WriteToPath(srcPath))
+
+			if !gzipped {
+				return srcPath
+			}
+
+			gzipPath := filepath.Join(tmpDir, "src.tar.gz")
+			src, err := os.Open(srcPath)
+			h.AssertNil(t, err)
+			defer src.Close()
+
+			dst, err := os.Create(gzipPath)
+			h.AssertNil(t, err)
+			defer dst.Close()
+
+			gzw := gzip.NewWriter(dst)
+			_, err = io.Copy(gzw, src)
+			h.AssertNil(t, err)
+			h.AssertNil(t, gzw.Close())
+
+			return gzipPath
+		}
+
+		for _, gzipped := range []bool{false, true} {
+			gzipped := gzipped
+			it(fmt.Sprintf("rewrites entries under basePath (gzipped=%t)", gzipped), func() {
+				src := writeSrcTar(gzipped)
+
+				tarFile := filepath.Join(tmpDir, "some.tar")
+				fh, err := os.Create(tarFile)
+				h.AssertNil(t, err)
+
+				tw := tar.NewWriter(fh)
+				err = archive.WriteTarToTar(tw, src, "/workspace", 1234, 2345, 0777, true, nil)
+				h.AssertNil(t, err)
+				h.AssertNil(t, tw.Close())
+				h.AssertNil(t, fh.Close())
+
+				file, err := os.Open(tarFile)
+				h.AssertNil(t, err)
+				defer file.Close()
+
+				tr := tar.NewReader(file)
+				verify := tarVerifier{t, tr, 1234, 2345}
+				verify.nextFile("/workspace/some-file.txt", "some-content", 0777)
+			})
+		}
+
+		when("fileFilter is provided", func() {
+			it("excludes files it rejects", func() {
+				src := writeSrcTar(false)
+
+				tarFile := filepath.Join(tmpDir, "some.tar")
+				fh, err := os.Create(tarFile)
+				h.AssertNil(t, err)
+
+				tw := tar.NewWriter(fh)
+				err = archive.WriteTarToTar(tw, src, "/workspace", 1234, 2345, 0777, true, func(string) bool { return false })
+				h.AssertNil(t, err)
+				h.AssertNil(t, tw.Close())
+				h.AssertNil(t, fh.Close())
+
+				file, err := os.Open(tarFile)
+				h.AssertNil(t, err)
+				defer file.Close()
+
+				_, err = tar.NewReader(file).Next()
+				h.AssertError(t, err, io.EOF.Error())
+			})
+		})
+	})
+
+	when("#IsGZip", func() {
+		it("returns true for gzip-compressed content", func() {
+			var buf bytes.Buffer
+			gzw := gzip.NewWriter(&buf)
+			_, err := gzw.Write([]byte("some-content"))
+			h.AssertNil(t, err)
+			h.AssertNil(t, gzw.Close())
+
+			isGZip, err := archive.IsGZip(&buf)
+			h.AssertNil(t, err)
+			h.AssertEq(t, isGZip, true)
+		})
+
+		it("returns false for non-gzip content", func() {
+			isGZip, err := archive.IsGZip(bytes.NewReader([]byte("not gzip")))
+			h.AssertNil(t, err)
+			h.AssertEq(t, isGZip, false)
+		})
+	})
+
+	when("#IsTar", func() {
+		it("returns true for a tar archive", func() {
+			tarBuilder := archive.TarBuilder{}
+			tarBuilder.AddFile("some-file.txt", 0644, archive.NormalizedDateTime, []byte("some-content"))
+
+			var buf bytes.Buffer
+			_, err := tarBuilder.WriteTo(&buf)
+			h.AssertNil(t, err)
+
+			isTar, err := archive.IsTar(&buf)
+			h.AssertNil(t, err)
+			h.AssertEq(t, isTar, true)
+		})
+
+		it("returns false for non-tar content", func() {
+			isTar, err := archive.IsTar(bytes.NewReader([]byte("not a tar")))
+			h.AssertNil(t, err)
+			h.AssertEq(t, isTar, false)
+		})
+	})
+
+	when("#SafePath", func() {
+		it("joins a normal relative entry onto dest", func() {
+			target, err := archive.SafePath("/some/dest", "bin/build")
+			h.AssertNil(t, err)
+			h.AssertEq(t, target, filepath.Join("/some/dest", "bin", "build"))
+		})
+
+		it("rejects an absolute path", func() {
+			_, err := archive.SafePath("/some/dest", "/etc/passwd")
+			h.AssertError(t, err, "absolute path")
+		})
+
+		it("rejects a path that escapes dest via '..'", func() {
+			_, err := archive.SafePath("/some/dest", "../../etc/passwd")
+			h.AssertError(t, err, "escapes destination directory")
+		})
+
+		it("rejects a path that escapes dest via internal '..' components", func() {
+			_, err := archive.SafePath("/some/dest", "bin/../../../etc/passwd")
+			h.AssertError(t, err, "escapes destination directory")
+		})
+	})
 }
 
 func fileMode(t *testing.T, path string) int64 {
@@ -460,3 +605,44 @@ func (v *tarVerifier) nextSymLink(name, link string) {
 		v.t.Fatalf(`expected %s to have been normalized, got: %s`, header.Name, header.ModTime.String())
 	}
 }
+
+// BenchmarkReadDirAsTarMemory archives a directory containing one large file and confirms heap
+// growth stays well under the file's size, demonstrating that ReadDirAsTar streams the archive
+// (applying fileFilter as it walks) instead of buffering it in memory.
+func BenchmarkReadDirAsTarMemory(b *testing.B) {
+	tmpDir, err := ioutil.TempDir("", "archive-benchmark")
+	if err != nil {
+		b.Fatalf("failed to create tmp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	const fileSize = 64 * 1024 * 1024
+	if err := ioutil.WriteFile(filepath.Join(tmpDir, "big-file"), make([]byte, fileSize), 0644); err != nil {
+		b.Fatalf("failed to create large file: %s", err)
+	}
+
+	fileFilter := func(string) bool { return true }
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+
+		rc := archive.ReadDirAsTar(tmpDir, "/workspace", 0, 0, -1, false, fileFilter)
+		if _, err := io.Copy(ioutil.Discard, rc); err != nil {
+			b.Fatalf("failed to read tar: %s", err)
+		}
+		if err := rc.Close(); err != nil {
+			b.Fatalf("failed to close tar reader: %s", err)
+		}
+
+		runtime.ReadMemStats(&after)
+		heapGrowth := int64(after.HeapAlloc) - int64(before.HeapAlloc)
+		b.ReportMetric(float64(heapGrowth), "heap-growth-bytes/op")
+
+		if heapGrowth > fileSize/2 {
+			b.Fatalf("expected heap growth to stay well under the archived file size (%d bytes), got %d bytes", fileSize, heapGrowth)
+		}
+	}
+}
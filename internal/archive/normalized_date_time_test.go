@@ -0,0 +1,29 @@
+package archive
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizedDateTimeFromEnv(t *testing.T) {
+	assertTimeEq := func(t *testing.T, actual, expected time.Time) {
+		t.Helper()
+		if !actual.Equal(expected) {
+			t.Fatalf("expected %v, got %v", expected, actual)
+		}
+	}
+
+	defaultTime := time.Date(1980, time.January, 1, 0, 0, 1, 0, time.UTC)
+
+	t.Run("epoch is unset", func(t *testing.T) {
+		assertTimeEq(t, normalizedDateTimeFromEnv(""), defaultTime)
+	})
+
+	t.Run("epoch is a valid unix timestamp", func(t *testing.T) {
+		assertTimeEq(t, normalizedDateTimeFromEnv("1000000000"), time.Unix(1000000000, 0).UTC())
+	})
+
+	t.Run("epoch is not a valid unix timestamp", func(t *testing.T) {
+		assertTimeEq(t, normalizedDateTimeFromEnv("not-a-number"), defaultTime)
+	})
+}
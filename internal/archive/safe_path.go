@@ -0,0 +1,36 @@
+package archive
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IsUnsafe reports whether name -- a raw tar entry name -- is an absolute path or uses ".."
+// components to climb above the archive root, the hallmark of a zip-slip attack. Extraction
+// code should reject any entry for which this returns true before doing anything else with it.
+func IsUnsafe(name string) bool {
+	clean := path.Clean(name)
+	return path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../")
+}
+
+// SafePath joins name -- a tar entry's name -- onto dest and returns the result, or an error if
+// name is an absolute path or contains ".." components that would resolve outside dest (a
+// "zip slip"). Code that extracts tar entries to disk should call this on every entry before
+// writing to the path it returns.
+func SafePath(dest, name string) (string, error) {
+	if path.IsAbs(name) {
+		return "", errors.Errorf("archive entry %s has an absolute path", name)
+	}
+
+	cleanDest := filepath.Clean(dest)
+	target := filepath.Join(cleanDest, name)
+	if target != cleanDest && !strings.HasPrefix(target, cleanDest+string(os.PathSeparator)) {
+		return "", errors.Errorf("archive entry %s escapes destination directory", name)
+	}
+
+	return target, nil
+}
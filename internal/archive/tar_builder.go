@@ -21,6 +21,7 @@ type fileEntry struct {
 	mode     int64
 	modTime  time.Time
 	contents []byte
+	linkname string
 }
 
 func (t *TarBuilder) AddFile(path string, mode int64, modTime time.Time, contents []byte) {
@@ -42,6 +43,16 @@ func (t *TarBuilder) AddDir(path string, mode int64, modTime time.Time) {
 	})
 }
 
+func (t *TarBuilder) AddSymlink(path, target string, mode int64, modTime time.Time) {
+	t.files = append(t.files, fileEntry{
+		typeFlag: tar.TypeSymlink,
+		path:     path,
+		mode:     mode,
+		modTime:  modTime,
+		linkname: target,
+	})
+}
+
 func (t *TarBuilder) Reader() io.ReadCloser {
 	pr, pw := io.Pipe()
 	go func() {
@@ -78,6 +89,7 @@ func (t *TarBuilder) WriteTo(writer io.Writer) (int64, error) {
 			Size:     int64(len(f.contents)),
 			Mode:     f.mode,
 			ModTime:  f.modTime,
+			Linkname: f.linkname,
 		}); err != nil {
 			return written, err
 		}
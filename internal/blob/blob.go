@@ -3,8 +3,12 @@ package blob
 import (
 	"bytes"
 	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
+	"io/ioutil"
 	"os"
+	"sync"
 
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/pkg/errors"
@@ -14,18 +18,76 @@ import (
 
 type Blob interface {
 	Open() (io.ReadCloser, error)
+
+	// Size returns the size, in bytes, of Open's tar-formatted contents.
+	Size() (int64, error)
+
+	// Checksum returns the sha256 digest of Open's tar-formatted contents, formatted as
+	// "sha256:<hex>".
+	Checksum() (string, error)
+}
+
+// statCache lazily computes and caches the size and checksum of a Blob's contents, reading them
+// via open exactly once regardless of how many times Size and Checksum are called.
+type statCache struct {
+	once     sync.Once
+	size     int64
+	checksum string
+	err      error
+}
+
+func (c *statCache) compute(open func() (io.ReadCloser, error)) error {
+	c.once.Do(func() {
+		r, err := open()
+		if err != nil {
+			c.err = err
+			return
+		}
+		defer r.Close()
+
+		h := sha256.New()
+		size, err := io.Copy(h, r)
+		if err != nil {
+			c.err = errors.Wrap(err, "compute blob checksum")
+			return
+		}
+
+		c.size = size
+		c.checksum = "sha256:" + hex.EncodeToString(h.Sum(nil))
+	})
+	return c.err
 }
 
 type blob struct {
 	path string
+	stat statCache
 }
 
 func NewBlob(path string) Blob {
 	return &blob{path: path}
 }
 
+// Size returns the size, in bytes, of Open's tar-formatted contents, computed by reading the
+// blob once and cached for subsequent calls to Size and Checksum.
+func (b *blob) Size() (int64, error) {
+	if err := b.stat.compute(b.Open); err != nil {
+		return 0, err
+	}
+	return b.stat.size, nil
+}
+
+// Checksum returns the sha256 digest of Open's tar-formatted contents, formatted as
+// "sha256:<hex>", computed by reading the blob once and cached for subsequent calls to Size and
+// Checksum.
+func (b *blob) Checksum() (string, error) {
+	if err := b.stat.compute(b.Open); err != nil {
+		return "", err
+	}
+	return b.stat.checksum, nil
+}
+
 // Open returns an io.ReadCloser whose contents are in tar archive format
-func (b blob) Open() (r io.ReadCloser, err error) {
+func (b *blob) Open() (r io.ReadCloser, err error) {
 	fi, err := os.Stat(b.path)
 	if err != nil {
 		return nil, errors.Wrapf(err, "read blob at path '%s'", b.path)
@@ -62,6 +124,65 @@ func (b blob) Open() (r io.ReadCloser, err error) {
 	return rc, nil
 }
 
+type readerBlob struct {
+	data []byte
+	stat statCache
+}
+
+// NewBlobFromBytes returns a Blob backed by an in-memory byte slice already in tar (optionally
+// gzip-compressed) format, for callers with no filesystem path to wrap with NewBlob. Open may be
+// called any number of times; each call reads from the start of data.
+func NewBlobFromBytes(data []byte) Blob {
+	return &readerBlob{data: data}
+}
+
+// NewBlobFromReader returns a Blob backed by r's entire contents, which must be in tar
+// (optionally gzip-compressed) format. r is read to completion and buffered in memory
+// immediately, since a Blob must support being Open'd more than once but r may not be.
+func NewBlobFromReader(r io.Reader) (Blob, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "buffer blob contents")
+	}
+	return NewBlobFromBytes(data), nil
+}
+
+// Open returns an io.ReadCloser whose contents are in tar archive format
+func (b *readerBlob) Open() (io.ReadCloser, error) {
+	ok, err := isGZip(bytes.NewReader(b.data))
+	if err != nil {
+		return nil, errors.Wrap(err, "check header")
+	}
+	if !ok {
+		return ioutil.NopCloser(bytes.NewReader(b.data)), nil
+	}
+
+	gzr, err := gzip.NewReader(bytes.NewReader(b.data))
+	if err != nil {
+		return nil, errors.Wrap(err, "create gzip reader")
+	}
+	return gzr, nil
+}
+
+// Size returns the size, in bytes, of Open's tar-formatted contents, computed by reading the
+// blob once and cached for subsequent calls to Size and Checksum.
+func (b *readerBlob) Size() (int64, error) {
+	if err := b.stat.compute(b.Open); err != nil {
+		return 0, err
+	}
+	return b.stat.size, nil
+}
+
+// Checksum returns the sha256 digest of Open's tar-formatted contents, formatted as
+// "sha256:<hex>", computed by reading the blob once and cached for subsequent calls to Size and
+// Checksum.
+func (b *readerBlob) Checksum() (string, error) {
+	if err := b.stat.compute(b.Open); err != nil {
+		return "", err
+	}
+	return b.stat.checksum, nil
+}
+
 func isGZip(file io.ReadSeeker) (bool, error) {
 	b := make([]byte, 3)
 	if _, err := file.Seek(0, 0); err != nil {
@@ -1,8 +1,10 @@
 package blob_test
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/sclevine/spec"
@@ -58,6 +60,84 @@ func testBlob(t *testing.T, when spec.G, it spec.S) {
 					assertBlob(t, blob.NewBlob(blobPath))
 				})
 			})
+
+			when("bytes", func() {
+				it("returns a tar reader", func() {
+					tgzPath := h.CreateTGZ(t, blobDir, ".", -1)
+					defer os.Remove(tgzPath)
+
+					data, err := ioutil.ReadFile(tgzPath)
+					h.AssertNil(t, err)
+
+					assertBlob(t, blob.NewBlobFromBytes(data))
+				})
+
+				it("can be opened more than once", func() {
+					tgzPath := h.CreateTGZ(t, blobDir, ".", -1)
+					defer os.Remove(tgzPath)
+
+					data, err := ioutil.ReadFile(tgzPath)
+					h.AssertNil(t, err)
+
+					b := blob.NewBlobFromBytes(data)
+					assertBlob(t, b)
+					assertBlob(t, b)
+				})
+			})
+
+			when("reader", func() {
+				it("returns a tar reader", func() {
+					tgzPath := h.CreateTGZ(t, blobDir, ".", -1)
+					defer os.Remove(tgzPath)
+
+					fh, err := os.Open(tgzPath)
+					h.AssertNil(t, err)
+					defer fh.Close()
+
+					b, err := blob.NewBlobFromReader(fh)
+					h.AssertNil(t, err)
+
+					assertBlob(t, b)
+				})
+			})
+		})
+
+		when("#Size and #Checksum", func() {
+			var b blob.Blob
+
+			it.Before(func() {
+				b = blob.NewBlob(filepath.Join("testdata", "blob"))
+			})
+
+			it("reports the size and checksum of the tar-formatted contents", func() {
+				size, err := b.Size()
+				h.AssertNil(t, err)
+				if size <= 0 {
+					t.Fatalf("expected a positive size, got %d", size)
+				}
+
+				checksum, err := b.Checksum()
+				h.AssertNil(t, err)
+				if !strings.HasPrefix(checksum, "sha256:") {
+					t.Fatalf("expected checksum to be prefixed with 'sha256:', got %q", checksum)
+				}
+			})
+
+			it("caches the result across calls", func() {
+				size, err := b.Size()
+				h.AssertNil(t, err)
+
+				checksum, err := b.Checksum()
+				h.AssertNil(t, err)
+
+				secondSize, err := b.Size()
+				h.AssertNil(t, err)
+				h.AssertEq(t, secondSize, size)
+
+				secondChecksum, err := b.Checksum()
+				h.AssertNil(t, err)
+				h.AssertEq(t, secondChecksum, checksum)
+			})
 		})
 	})
 }
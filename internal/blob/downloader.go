@@ -64,6 +64,21 @@ func (d *downloader) Download(ctx context.Context, pathOrURI string) (Blob, erro
 	return &blob{path: path}, nil
 }
 
+// NewDownloadBlob returns a Blob backed by downloadURL's contents, fetched over HTTP(S) and
+// cached under cacheDir, keyed by downloadURL and revalidated against it with a conditional
+// request on every call (the same caching NewDownloader's Download uses for "http"/"https"
+// URIs). Open serves the cached copy once NewDownloadBlob has fetched or revalidated it.
+func NewDownloadBlob(downloadURL, cacheDir string) (Blob, error) {
+	d := NewDownloader(logging.New(ioutil.Discard), cacheDir)
+
+	path, err := d.handleHTTP(context.Background(), downloadURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &blob{path: path}, nil
+}
+
 func (d *downloader) handleFile(path string) string {
 	path, err := filepath.Abs(path)
 	if err != nil {
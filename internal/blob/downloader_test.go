@@ -27,6 +27,12 @@ func TestDownloader(t *testing.T) {
 	spec.Run(t, "Downloader", testDownloader, spec.Sequential(), spec.Report(report.Terminal{}))
 }
 
+func TestDownloadBlob(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "DownloadBlob", testDownloadBlob, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
 func testDownloader(t *testing.T, when spec.G, it spec.S) {
 	when("#Download", func() {
 		var (
@@ -162,6 +168,77 @@ func testDownloader(t *testing.T, when spec.G, it spec.S) {
 	})
 }
 
+func testDownloadBlob(t *testing.T, when spec.G, it spec.S) {
+	when("#NewDownloadBlob", func() {
+		var (
+			cacheDir string
+			server   *ghttp.Server
+			uri      string
+			tgz      string
+		)
+
+		it.Before(func() {
+			var err error
+			cacheDir, err = ioutil.TempDir("", "cache")
+			h.AssertNil(t, err)
+
+			server = ghttp.NewServer()
+			uri = server.URL() + "/downloader/somefile.tgz"
+
+			tgz = h.CreateTGZ(t, filepath.Join("testdata", "blob"), "./", 0777)
+		})
+
+		it.After(func() {
+			h.AssertNil(t, os.RemoveAll(cacheDir))
+			os.Remove(tgz)
+			server.Close()
+		})
+
+		when("url is valid", func() {
+			it.Before(func() {
+				server.AppendHandlers(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Add("ETag", "A")
+					http.ServeFile(w, r, tgz)
+				})
+
+				server.AppendHandlers(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(304)
+				})
+			})
+
+			it("downloads and caches the blob", func() {
+				b, err := blob.NewDownloadBlob(uri, cacheDir)
+				h.AssertNil(t, err)
+				assertBlob(t, b)
+			})
+
+			it("revalidates with a conditional request on the next call", func() {
+				b, err := blob.NewDownloadBlob(uri, cacheDir)
+				h.AssertNil(t, err)
+				assertBlob(t, b)
+
+				b, err = blob.NewDownloadBlob(uri, cacheDir)
+				h.AssertNil(t, err)
+				assertBlob(t, b)
+			})
+		})
+
+		when("server returns a non-200 response", func() {
+			it.Before(func() {
+				server.AppendHandlers(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(404)
+				})
+			})
+
+			it("returns a clear error", func() {
+				_, err := blob.NewDownloadBlob(uri, cacheDir)
+				h.AssertError(t, err, "could not download")
+				h.AssertError(t, err, "http status '404'")
+			})
+		})
+	})
+}
+
 func assertBlob(t *testing.T, b blob.Blob) {
 	t.Helper()
 	r, err := b.Open()
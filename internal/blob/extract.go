@@ -0,0 +1,85 @@
+package blob
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/archive"
+)
+
+// ExtractUnder walks b's tar contents and writes only the entries whose path is under prefix
+// into dest, preserving their relative path (below prefix) and file mode. It's meant for
+// inspection tooling that wants a handful of files out of a blob -- e.g. buildpack.toml and
+// bin/* -- without materializing the whole thing.
+func ExtractUnder(b Blob, prefix, dest string) error {
+	rc, err := b.Open()
+	if err != nil {
+		return errors.Wrap(err, "open blob")
+	}
+	defer rc.Close()
+
+	prefix = path.Clean(prefix)
+
+	tr := tar.NewReader(rc)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "reading blob")
+		}
+
+		if archive.IsUnsafe(header.Name) {
+			return errors.Errorf("blob entry '%s' is outside the archive root", header.Name)
+		}
+
+		name := path.Clean(header.Name)
+		if name != prefix && !strings.HasPrefix(name, prefix+"/") {
+			continue
+		}
+
+		relPath, err := filepath.Rel(prefix, name)
+		if err != nil {
+			return errors.Wrapf(err, "relativize entry '%s'", header.Name)
+		}
+
+		target, err := archive.SafePath(dest, relPath)
+		if err != nil {
+			return errors.Wrapf(err, "blob entry '%s'", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return errors.Wrapf(err, "creating directory '%s'", target)
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return errors.Wrapf(err, "creating directory '%s'", filepath.Dir(target))
+			}
+			if err := writeExtractedFile(target, header.Mode, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeExtractedFile(target string, mode int64, r io.Reader) error {
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(mode))
+	if err != nil {
+		return errors.Wrapf(err, "creating file '%s'", target)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return errors.Wrapf(err, "writing file '%s'", target)
+	}
+	return nil
+}
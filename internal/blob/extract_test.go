@@ -0,0 +1,86 @@
+package blob_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/archive"
+	"github.com/buildpacks/pack/internal/blob"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestExtractUnder(t *testing.T) {
+	spec.Run(t, "ExtractUnder", testExtractUnder, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testExtractUnder(t *testing.T, when spec.G, it spec.S) {
+	when("#ExtractUnder", func() {
+		var (
+			srcDir, destDir string
+		)
+
+		it.Before(func() {
+			var err error
+			srcDir, err = ioutil.TempDir("", "extract-under-src")
+			h.AssertNil(t, err)
+
+			h.AssertNil(t, ioutil.WriteFile(filepath.Join(srcDir, "buildpack.toml"), []byte("some-toml"), 0644))
+			h.AssertNil(t, os.MkdirAll(filepath.Join(srcDir, "bin"), 0755))
+			h.AssertNil(t, ioutil.WriteFile(filepath.Join(srcDir, "bin", "detect"), []byte("detect-contents"), 0755))
+			h.AssertNil(t, ioutil.WriteFile(filepath.Join(srcDir, "bin", "build"), []byte("build-contents"), 0755))
+
+			destDir, err = ioutil.TempDir("", "extract-under-dest")
+			h.AssertNil(t, err)
+		})
+
+		it.After(func() {
+			h.AssertNil(t, os.RemoveAll(srcDir))
+			h.AssertNil(t, os.RemoveAll(destDir))
+		})
+
+		it("writes only entries under the prefix, preserving relative paths and modes", func() {
+			err := blob.ExtractUnder(blob.NewBlob(srcDir), "bin", destDir)
+			h.AssertNil(t, err)
+
+			detectContents, err := ioutil.ReadFile(filepath.Join(destDir, "detect"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(detectContents), "detect-contents")
+
+			buildContents, err := ioutil.ReadFile(filepath.Join(destDir, "build"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(buildContents), "build-contents")
+
+			detectInfo, err := os.Stat(filepath.Join(destDir, "detect"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, detectInfo.Mode().Perm(), os.FileMode(0755))
+
+			_, err = os.Stat(filepath.Join(destDir, "buildpack.toml"))
+			h.AssertTrue(t, os.IsNotExist(err))
+		})
+
+		it("rejects a malicious blob entry using '..' to climb above the archive root", func() {
+			tarBuilder := archive.TarBuilder{}
+			tarBuilder.AddFile("../../../etc/passwd", 0644, archive.NormalizedDateTime, []byte("uh-oh"))
+			data, err := ioutil.ReadAll(tarBuilder.Reader())
+			h.AssertNil(t, err)
+
+			err = blob.ExtractUnder(blob.NewBlobFromBytes(data), ".", destDir)
+			h.AssertError(t, err, "outside the archive root")
+		})
+
+		it("rejects a malicious blob entry with an absolute path", func() {
+			tarBuilder := archive.TarBuilder{}
+			tarBuilder.AddFile("/etc/passwd", 0644, archive.NormalizedDateTime, []byte("uh-oh"))
+			data, err := ioutil.ReadAll(tarBuilder.Reader())
+			h.AssertNil(t, err)
+
+			err = blob.ExtractUnder(blob.NewBlobFromBytes(data), ".", destDir)
+			h.AssertError(t, err, "outside the archive root")
+		})
+	})
+}
@@ -0,0 +1,38 @@
+package blob
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+type verifiedBlob struct {
+	Blob
+	expectedChecksum string
+}
+
+// NewVerifiedBlob wraps b so that Open returns an error if b's checksum doesn't match
+// expectedChecksum (formatted as "sha256:<hex>", the same format Checksum returns), checked
+// before any bytes are returned to the caller. This lets distributors of a buildpack publish a
+// sha256 alongside it and have consumers refuse to build with a tampered download.
+func NewVerifiedBlob(b Blob, expectedChecksum string) Blob {
+	return &verifiedBlob{Blob: b, expectedChecksum: expectedChecksum}
+}
+
+func (b *verifiedBlob) Open() (io.ReadCloser, error) {
+	checksum, err := b.Blob.Checksum()
+	if err != nil {
+		return nil, err
+	}
+
+	if checksum != b.expectedChecksum {
+		return nil, errors.Errorf(
+			"checksum mismatch: expected %s, got %s",
+			style.Symbol(b.expectedChecksum), style.Symbol(checksum),
+		)
+	}
+
+	return b.Blob.Open()
+}
@@ -0,0 +1,38 @@
+package blob_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/blob"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestVerifiedBlob(t *testing.T) {
+	spec.Run(t, "VerifiedBlob", testVerifiedBlob, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testVerifiedBlob(t *testing.T, when spec.G, it spec.S) {
+	when("#NewVerifiedBlob", func() {
+		var underlying blob.Blob
+
+		it.Before(func() {
+			underlying = blob.NewBlob(filepath.Join("testdata", "blob"))
+		})
+
+		it("opens normally when the checksum matches", func() {
+			checksum, err := underlying.Checksum()
+			h.AssertNil(t, err)
+
+			assertBlob(t, blob.NewVerifiedBlob(underlying, checksum))
+		})
+
+		it("errors without opening when the checksum doesn't match", func() {
+			_, err := blob.NewVerifiedBlob(underlying, "sha256:0000000000000000000000000000000000000000000000000000000000000000").Open()
+			h.AssertError(t, err, "checksum mismatch")
+		})
+	})
+}
@@ -0,0 +1,46 @@
+package build
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// validateAnnotations applies the same reverse-DNS and reserved-namespace rules as
+// validateLabels -- annotations and labels are both OCI key/value metadata, and the lifecycle
+// reserves the same io.buildpacks. namespace for both.
+func validateAnnotations(annotations map[string]string) error {
+	for key := range annotations {
+		if !labelKeyPattern.MatchString(key) {
+			return errors.Errorf("invalid annotation %s: keys must follow the reverse-DNS convention (e.g. org.opencontainers.image.source)", style.Symbol(key))
+		}
+		if strings.HasPrefix(key, reservedLabelNamespace) {
+			return errors.Errorf("invalid annotation %s: the %s namespace is reserved for the lifecycle", style.Symbol(key), style.Symbol(reservedLabelNamespace))
+		}
+	}
+	return nil
+}
+
+// annotationArgs returns a -annotation key=value pair for each annotation, sorted by key so
+// repeated calls produce the same phase arguments.
+func annotationArgs(annotations map[string]string) []string {
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	args := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		args = append(args, "-annotation", fmt.Sprintf("%s=%s", key, annotations[key]))
+	}
+	return args
+}
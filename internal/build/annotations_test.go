@@ -0,0 +1,40 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestAnnotationArgs(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "annotationArgs", testAnnotationArgs, spec.Report(report.Terminal{}))
+}
+
+func testAnnotationArgs(t *testing.T, when spec.G, it spec.S) {
+	when("no annotations are given", func() {
+		it("returns no args", func() {
+			h.AssertEq(t, annotationArgs(nil), []string(nil))
+		})
+	})
+
+	when("annotations are given", func() {
+		it("sorts them by key for deterministic output", func() {
+			annotations := map[string]string{
+				"org.opencontainers.image.b": "2",
+				"org.opencontainers.image.a": "1",
+			}
+
+			h.AssertEq(t, annotationArgs(annotations), []string{
+				"-annotation", "org.opencontainers.image.a=1",
+				"-annotation", "org.opencontainers.image.b=2",
+			})
+		})
+	})
+}
@@ -2,16 +2,27 @@ package build
 
 import (
 	"context"
-	"math/rand"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/Masterminds/semver"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/volume/mounts"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 
+	"github.com/buildpacks/pack/internal/api"
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/cache"
+	"github.com/buildpacks/pack/internal/image"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/logging"
 )
@@ -19,6 +30,11 @@ import (
 var (
 	// SupportedPlatformAPIVersions lists the Platform API versions pack supports.
 	SupportedPlatformAPIVersions = []string{"0.2", "0.3"}
+
+	// minimumSupportedLifecycleVersion is the oldest lifecycle version Execute will run against.
+	// Older lifecycles predate Platform API negotiation and fail deep inside a phase (often
+	// EXPORTING) with an error that gives no hint the real problem is the builder's age.
+	minimumSupportedLifecycleVersion = semver.MustParse("0.5.0")
 )
 
 type Builder interface {
@@ -29,31 +45,75 @@ type Builder interface {
 }
 
 type Lifecycle struct {
-	builder            Builder
-	logger             logging.Logger
-	docker             client.CommonAPIClient
-	appPath            string
-	appOnce            *sync.Once
-	httpProxy          string
-	httpsProxy         string
-	noProxy            string
-	version            string
-	platformAPIVersion string
-	LayersVolume       string
-	AppVolume          string
-	Volumes            []string
-	DefaultProcessType string
-	fileFilter         func(string) bool
+	builder                  Builder
+	logger                   logging.Logger
+	docker                   client.CommonAPIClient
+	appPath                  string
+	additionalAppPaths       []string
+	appOnce                  *sync.Once
+	httpProxy                string
+	httpsProxy               string
+	noProxy                  string
+	version                  string
+	platformAPIVersion       string
+	LayersVolume             string
+	AppVolume                string
+	PlatformVolume           string
+	Volumes                  []string
+	DefaultProcessType       string
+	fileFilter               func(string) bool
+	paths                    mountPaths
+	ownsLayersVolume         bool
+	keychain                 authn.Keychain
+	platformFiles            map[string][]byte
+	platformOnce             *sync.Once
+	groupAdd                 []string
+	digest                   string
+	additionalTags           []string
+	ociLayoutDir             string
+	orderPath                string
+	lifecycleVolume          string
+	resources                Resources
+	processOverrides         map[string]ProcessOverride
+	cacheImage               string
+	labels                   map[string]string
+	annotations              map[string]string
+	runImage                 string
+	skipMissingPreviousImage bool
+	os                       string
 }
 
 type Cache interface {
 	Name() string
 	Clear(context.Context) error
+	// Type returns the cache's kind, e.g. "build" or "launch".
+	Type() string
 }
 
-func init() {
-	rand.Seed(time.Now().UTC().UnixNano())
-}
+// CacheBackend selects the storage mechanism backing the build and launch caches.
+type CacheBackend string
+
+const (
+	// VolumeCacheBackend stores caches in Docker named volumes. This is the default.
+	VolumeCacheBackend CacheBackend = "volume"
+	// BindCacheBackend stores caches in host directories, bind-mounted into the lifecycle
+	// containers.
+	BindCacheBackend CacheBackend = "bind"
+)
+
+// PullPolicy controls whether Execute pulls the builder image before using it.
+type PullPolicy string
+
+const (
+	// PullAlways always pulls the builder image, even if it's already present locally.
+	PullAlways PullPolicy = "always"
+	// PullIfNotPresent pulls the builder image only if it isn't already present locally. This
+	// is the default.
+	PullIfNotPresent PullPolicy = "if-not-present"
+	// PullNever never pulls the builder image; Execute fails with whatever error the daemon
+	// returns if it isn't already present.
+	PullNever PullPolicy = "never"
+)
 
 func NewLifecycle(docker client.CommonAPIClient, logger logging.Logger) *Lifecycle {
 	l := &Lifecycle{logger: logger, docker: docker}
@@ -61,100 +121,843 @@ func NewLifecycle(docker client.CommonAPIClient, logger logging.Logger) *Lifecyc
 	return l
 }
 
+// PlatformAPIVersion returns the Platform API version negotiated with the builder during Setup.
+func (l *Lifecycle) PlatformAPIVersion() string {
+	return l.platformAPIVersion
+}
+
+// isWindows reports whether the builder's phase containers run Windows, per TargetOS. Phase
+// construction checks this to skip container options that only make sense for Linux containers,
+// such as the root user and supplementary GroupAdd GIDs.
+func (l *Lifecycle) isWindows() bool {
+	return l.os == "windows"
+}
+
+// Digest returns the sha256 digest of the image EXPORTING (or CREATING) published to a registry,
+// or "" if Execute hasn't published an image -- for example, because it exported to the daemon
+// instead, or hasn't run EXPORTING/CREATING yet.
+func (l *Lifecycle) Digest() string {
+	return l.digest
+}
+
 type LifecycleOptions struct {
-	AppPath            string
-	Image              name.Reference
-	Builder            Builder
-	RunImage           string
-	ClearCache         bool
-	Publish            bool
-	HTTPProxy          string
-	HTTPSProxy         string
-	NoProxy            string
-	Network            string
+	AppPath string
+
+	// AppPaths, when set, names additional app source directories or zips merged into the app
+	// volume after AppPath, in order. Each source is copied in its entirety; a file present in
+	// more than one source (AppPath or a later entry in AppPaths) ends up with the content from
+	// the last source that has it, while files unique to an earlier source are left untouched --
+	// the sources are merged, not replaced. FileFilter, when set, is applied to every source the
+	// same way it's applied to AppPath.
+	AppPaths    []string
+	Image       name.Reference
+	Builder     Builder
+	RunImage    string
+	ClearCache  bool
+	SkipRestore bool
+
+	// RunImageMirrors, when set, names additional locations for RunImage. Setup picks whichever
+	// one (including RunImage itself) is hosted on the same registry as Image, the way the
+	// lifecycle selects a run image mirror at ANALYZING time, and falls back to RunImage if none
+	// of them match.
+	RunImageMirrors []string
+
+	// RunImageTarball, when set, names a tar file Execute loads into the daemon before
+	// EXPORTING, for air-gapped builds where the run image can't be pulled from a registry. It
+	// must contain the run image Setup resolves from RunImage and RunImageMirrors; Execute fails
+	// if it doesn't.
+	RunImageTarball string
+
+	// SkipMissingPreviousImage, when true, makes Analyze check whether Image already exists
+	// before ANALYZING, and skip restoring layer metadata (as if ClearCache were set) when it
+	// doesn't, rather than letting the lifecycle's own attempt to pull a nonexistent image
+	// surface as a warning. A genuine problem reaching the daemon or registry (bad credentials,
+	// network failure) still fails the build.
+	SkipMissingPreviousImage bool
+
+	// ClearLaunchCache, when true, clears the launch cache Execute creates before EXPORTING,
+	// independent of ClearCache. Unlike ClearCache, it has no effect on RESTORING.
+	ClearLaunchCache bool
+	Publish          bool
+	HTTPProxy        string
+	HTTPSProxy       string
+	NoProxy          string
+	Network          string
+
+	// DetectNetwork, if set, overrides Network for the DETECTING phase only. Falls back to
+	// Network when empty. Like Network, a value of "none" is passed straight through to the
+	// container's NetworkMode, giving the detector container no network access at all.
+	DetectNetwork string
+
+	// BuildNetwork, if set, overrides Network for the BUILDING phase only. Falls back to
+	// Network when empty.
+	BuildNetwork string
+
 	Volumes            []string
 	DefaultProcessType string
-	FileFilter         func(string) bool
+
+	// FileFilter, when set, is used as-is to decide which paths under AppPath are uploaded. When
+	// left unset, Setup looks for a .buildignore file directly under AppPath and, if found,
+	// builds a FileFilter from its gitignore-style patterns via NewIgnoreFilter. An explicit
+	// FileFilter always takes precedence over a discovered .buildignore.
+	FileFilter func(string) bool
+
+	// AppDirName overrides the name of the directory the app source is mounted under inside the
+	// lifecycle containers. It defaults to "workspace".
+	AppDirName string
+
+	// TargetOS names the OS the builder's phase containers run ("linux" or "windows"). It
+	// controls the mount path syntax Setup resolves (`c:\...` instead of `/...`) and which
+	// Linux-only container options (root user, supplementary GroupAdd GIDs) phases apply.
+	// Defaults to "linux" when left unset.
+	TargetOS string
+
+	// ProjectDescriptorPath, when set, names a project descriptor (project.toml) Setup reads and
+	// applies: its [build.env] entries are written into the platform directory's env/ folder
+	// alongside PlatformFiles, and its [build] exclude patterns become a FileFilter via
+	// NewIgnoreFilter. An explicit FileFilter always takes precedence over the descriptor's
+	// exclude patterns, the same way it takes precedence over a discovered .buildignore; an entry
+	// in PlatformFiles always takes precedence over the same env/ path derived from the
+	// descriptor.
+	ProjectDescriptorPath string
+
+	// CacheBackend selects how the build and launch caches are stored. It defaults to
+	// VolumeCacheBackend when left unset.
+	CacheBackend CacheBackend
+	// BindCacheDir is the host directory under which the build and launch caches are stored
+	// when CacheBackend is BindCacheBackend. It is ignored otherwise.
+	BindCacheDir string
+
+	// BuildCacheName and LaunchCacheName, when set, override the volume names that would
+	// otherwise be derived from Image. They have no effect when CacheBackend is
+	// BindCacheBackend.
+	BuildCacheName  string
+	LaunchCacheName string
+
+	// CacheImage, when set, names a registry-hosted image ANALYZING, RESTORING, and EXPORTING
+	// (or CREATING) use as the build cache instead of a local volume or bind-mounted directory,
+	// via the lifecycle's `-cache-image` flag -- useful for ephemeral CI runners with no
+	// persistent volume to reuse between builds. It is mutually exclusive with CacheBackend and
+	// BuildCacheName, which only affect the build cache's volume/bind representation; Setup
+	// returns an error if either is set alongside CacheImage. It requires a negotiated Platform
+	// API of cacheImagePlatformAPI or newer; Execute logs a warning and falls back to a cache
+	// volume on older platforms. The launch cache is unaffected, since it is never backed by a
+	// registry.
+	CacheImage string
+
+	// Labels, when set, are passed to EXPORTING (or CREATING) via the lifecycle's `-label` flag
+	// and set on the produced image. Keys must follow the reverse-DNS convention (e.g.
+	// com.example.git-sha) and may not fall within the io.buildpacks. namespace, which the
+	// lifecycle reserves for its own build and project metadata labels; Setup returns an error
+	// otherwise.
+	Labels map[string]string
+
+	// Annotations, when set, are passed to EXPORTING (or CREATING) via the lifecycle's
+	// `-annotation` flag and set on the produced image's manifest, separately from Labels -- this
+	// is a first step towards annotating the OCI index pack will produce once it supports
+	// exporting multi-architecture images. Keys follow the same reverse-DNS and reserved-namespace
+	// rules as Labels; Setup returns an error otherwise.
+	Annotations map[string]string
+
+	// Timeout, when non-zero, bounds the combined duration of Execute's phases, from PREPARING
+	// through EXPORTING (or CREATING). Pulling the builder, Setup, and preparing LifecycleImage
+	// and RunImageTarball happen before the timeout starts and are not bounded by it. If it
+	// elapses while a phase is running, Execute returns early with an error naming the phase that
+	// was in progress.
+	Timeout time.Duration
+
+	// OnPhaseComplete, when set, is invoked after each phase of Execute (PREPARING, DETECTING,
+	// ANALYZING, RESTORING, PRE_BUILD, BUILDING, EXPORTING, or CREATING) with the wall-clock
+	// duration that phase took. A nil callback is a no-op.
+	OnPhaseComplete func(phase string, d time.Duration)
+
+	// DryRun, when true, causes Execute to log the container configuration (image, command, env,
+	// binds, network) each phase would have used instead of actually creating and running
+	// containers, and skips clearing the cache. Useful for debugging builder misconfiguration
+	// without performing a real build.
+	DryRun bool
+
+	// ReuseLayersVolume, when set, names an existing Docker volume to mount as the layers
+	// volume instead of allocating a fresh pack-layers-<random> one. pack never deletes a
+	// volume it didn't create, so Cleanup leaves this volume in place for a later build to
+	// reuse. Leave unset to preserve the default behavior of a fresh, disposable volume.
+	ReuseLayersVolume string
+
+	// StartPhase, when set, skips every split phase before it (one of "DETECTING", "ANALYZING",
+	// "RESTORING", "BUILDING", or "EXPORTING"), resuming a build that already got partway
+	// through -- for example, retrying just EXPORTING after a transient registry error. It
+	// requires ReuseLayersVolume, since the skipped phases' results must already be present in
+	// the reused layers volume; Execute returns an error rather than resuming from empty state.
+	// It has no effect -- and is rejected -- when the builder's lifecycle runs the combined
+	// creator phase, which cannot be resumed partway through.
+	StartPhase string
+
+	// LogWriter, when set, receives a copy of everything the lifecycle logs, including phase
+	// container stdout/stderr, in addition to the configured Logger -- for example, a file kept
+	// open for the duration of a long build so scrollback isn't lost. A nil LogWriter (the
+	// default) disables the tee.
+	LogWriter io.Writer
+
+	// PullBuilder controls whether Execute pulls Builder's image before using it. It defaults
+	// to PullIfNotPresent when left unset.
+	PullBuilder PullPolicy
+
+	// RegistryAuth provides credentials for the registries that ANALYZING pulls the previous
+	// image from and EXPORTING (and CREATING) push to when Publish is true, keyed by registry
+	// host (e.g. "index.docker.io", "my-registry.example.com") with an auth header value (e.g.
+	// "Basic dXNlcjpwYXNz" or "Bearer asdf="), the same format as the lifecycle's
+	// CNB_REGISTRY_AUTH environment variable. An entry here takes priority over the ambient
+	// Docker client config (~/.docker/config.json) for its registry; any registry not present in
+	// RegistryAuth still falls back to that ambient config.
+	RegistryAuth map[string]string
+
+	// PlatformFiles, when non-empty, names files Setup writes into a dedicated volume mounted at
+	// the platform directory before DETECTING, keyed by their path relative to the platform
+	// directory (e.g. "project-metadata.toml") with the file's contents. Use this to hand the
+	// lifecycle a single file without bind-mounting an entire host directory over the platform
+	// dir.
+	PlatformFiles map[string][]byte
+
+	// GroupAdd lists supplementary GIDs added to every phase container's process via Docker's
+	// GroupAdd, on top of the single GID Builder.GID() already runs as. Use this when a buildpack
+	// needs access to something owned by an additional group, such as a mounted Docker socket.
+	// Defaults to no supplementary groups.
+	GroupAdd []string
+
+	// PreBuildHook, when set, describes a container Execute runs between RESTORING and BUILDING,
+	// with the layers and app volumes mounted at their usual paths -- for example, to scan
+	// restored layers for license compliance before BUILDING starts. A failure aborts the build
+	// with a FailedPhaseError naming the hook's exit code. A nil PreBuildHook (the default) is a
+	// no-op. It has no effect -- and is rejected -- when the builder's lifecycle runs the combined
+	// creator phase, since there is no boundary between RESTORING and BUILDING to run it at.
+	PreBuildHook *PreBuildHook
+
+	// AdditionalTags names extra tags EXPORTING (or CREATING) writes the same layers under,
+	// alongside Image, in a single pass. Each entry must resolve to the same registry as Image
+	// when Publish is set; Setup returns an error otherwise, since a single EXPORTING pass
+	// authenticates against (and publishes to) only one registry.
+	AdditionalTags []string
+
+	// ExportToOCILayout, when set, names a host directory EXPORTING (or CREATING) writes an OCI
+	// image layout into instead of pushing to a registry or loading into the Docker daemon --
+	// for air-gapped builds where neither is reachable. It is mutually exclusive with Publish;
+	// Setup returns an error if both are set, since an export can only write to one destination.
+	ExportToOCILayout string
+
+	// OrderPath, when set, names a host order.toml DETECTING mounts over the builder's own and
+	// passes via the lifecycle's `-order` flag, letting callers experiment with a buildpack
+	// group/order that differs from the one baked into the builder image. It requires a
+	// negotiated Platform API of orderOverridePlatformAPI or newer; Execute logs a warning and
+	// falls back to the builder's own order.toml on older platforms.
+	OrderPath string
+
+	// LifecycleImage, when set, names an image containing lifecycle binaries at /cnb/lifecycle
+	// that Execute mounts over each phase container's own, instead of using the ones embedded in
+	// Builder -- for builders that don't bundle a lifecycle. Execute pulls the image if it isn't
+	// present locally, and fails if the image doesn't declare support for the negotiated
+	// Platform API.
+	LifecycleImage string
+
+	// Resources constrains the CPU and memory each phase container may use. Its zero value
+	// leaves every phase container unconstrained, the default behavior.
+	Resources Resources
+
+	// ProcessOverrides maps a process type (as declared in launch.toml, e.g. "worker") to a
+	// ProcessOverride EXPORTING (or CREATING) should apply to it. This lifecycle doesn't expose a
+	// flag or file for overriding a process's working directory or args at export time, so Execute
+	// logs a warning and leaves the process as the builder produced it rather than silently
+	// dropping the override.
+	ProcessOverrides map[string]ProcessOverride
 }
 
-func (l *Lifecycle) Execute(ctx context.Context, opts LifecycleOptions) error {
-	l.Setup(opts)
-	defer l.Cleanup()
+// ProcessOverride describes a per-process working directory and/or args override that
+// LifecycleOptions.ProcessOverrides requests EXPORTING (or CREATING) apply to a process type.
+type ProcessOverride struct {
+	// WorkingDirectory, when set, overrides the directory the process's command runs from.
+	WorkingDirectory string
+	// Args, when set, overrides the process's args.
+	Args []string
+}
+
+// Resources constrains the CPU and memory a phase container may use, applied to its Docker host
+// config the same way `docker run --cpu-period --cpu-quota --memory` would be. Zero values mean
+// unlimited.
+type Resources struct {
+	// CPUPeriod and CPUQuota together bound CPU time: CPUQuota is the number of microseconds of
+	// CPU time the container gets every CPUPeriod microseconds.
+	CPUPeriod int64
+	CPUQuota  int64
+
+	// Memory bounds the container's memory usage, in bytes.
+	Memory int64
+}
+
+// PreBuildHook describes a container LifecycleOptions.PreBuildHook runs between RESTORING and
+// BUILDING.
+type PreBuildHook struct {
+	// Image is the name of the image to run the hook in.
+	Image string
+	// Command is the command run inside Image, in exec form (no shell).
+	Command []string
+}
+
+// splitPhaseOrder lists the phases of a non-creator build in the order Execute runs them.
+var splitPhaseOrder = []string{"DETECTING", "ANALYZING", "RESTORING", "BUILDING", "EXPORTING"}
 
-	buildCache := cache.NewVolumeCache(opts.Image, "build", l.docker)
-	launchCache := cache.NewVolumeCache(opts.Image, "launch", l.docker)
-	l.logger.Debugf("Using build cache volume %s", style.Symbol(buildCache.Name()))
+// startPhaseIndex validates opts.StartPhase and returns the index in splitPhaseOrder to resume
+// from. It returns 0 (resume from the beginning) when StartPhase is unset.
+func startPhaseIndex(opts LifecycleOptions) (int, error) {
+	if opts.StartPhase == "" {
+		return 0, nil
+	}
 
-	if opts.ClearCache {
-		if err := buildCache.Clear(ctx); err != nil {
-			return errors.Wrap(err, "clearing build cache")
+	if opts.ReuseLayersVolume == "" {
+		return 0, errors.Errorf("StartPhase %s requires ReuseLayersVolume so the prior phases' results are available in the layers volume", style.Symbol(opts.StartPhase))
+	}
+
+	for i, phase := range splitPhaseOrder {
+		if phase == opts.StartPhase {
+			return i, nil
 		}
-		l.logger.Debugf("Build cache %s cleared", style.Symbol(buildCache.Name()))
 	}
+	return 0, errors.Errorf("unknown start phase %s", style.Symbol(opts.StartPhase))
+}
+
+// timePhase runs fn and, if opts.OnPhaseComplete is set, reports how long it took under phase.
+func timePhase(opts LifecycleOptions, phase string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	if opts.OnPhaseComplete != nil {
+		opts.OnPhaseComplete(phase, time.Since(start))
+	}
+	return err
+}
 
-	phaseFactory := NewDefaultPhaseFactory(l)
+// clearCacheIfRequested clears c when clear is true, logging what it did (or would have done, for
+// dryRun). It's a no-op when clear is false.
+func clearCacheIfRequested(ctx context.Context, clear, dryRun bool, c Cache, logger logging.Logger) error {
+	if !clear {
+		return nil
+	}
 
-	l.logger.Info(style.Step("DETECTING"))
-	if err := l.Detect(ctx, opts.Network, opts.Volumes, phaseFactory); err != nil {
-		return err
+	if dryRun {
+		logger.Debugf("Dry run: skipping clearing %s cache %s", c.Type(), style.Symbol(c.Name()))
+		return nil
 	}
 
-	l.logger.Info(style.Step("ANALYZING"))
-	if err := l.Analyze(ctx, opts.Image.Name(), buildCache.Name(), opts.Publish, opts.ClearCache, phaseFactory); err != nil {
+	if err := c.Clear(ctx); err != nil {
+		return errors.Wrapf(err, "clearing %s cache", c.Type())
+	}
+	logger.Debugf("%s cache %s cleared", c.Type(), style.Symbol(c.Name()))
+	return nil
+}
+
+func (l *Lifecycle) Execute(ctx context.Context, opts LifecycleOptions) error {
+	if err := l.pullBuilderIfNeeded(ctx, opts); err != nil {
 		return err
 	}
 
-	l.logger.Info(style.Step("RESTORING"))
-	if opts.ClearCache {
-		l.logger.Info("Skipping 'restore' due to clearing cache")
-	} else if err := l.Restore(ctx, buildCache.Name(), phaseFactory); err != nil {
+	if err := l.Setup(opts); err != nil {
 		return err
 	}
+	defer l.Cleanup()
 
-	l.logger.Info(style.Step("BUILDING"))
+	if err := l.prepareLifecycleImage(ctx, opts.LifecycleImage); err != nil {
+		return err
+	}
 
-	if err := l.Build(ctx, opts.Network, opts.Volumes, phaseFactory); err != nil {
+	if err := l.prepareRunImageTarball(ctx, opts.RunImageTarball); err != nil {
 		return err
 	}
 
-	l.logger.Info(style.Step("EXPORTING"))
-	if err := l.Export(ctx, opts.Image.Name(), opts.RunImage, opts.Publish, launchCache.Name(), buildCache.Name(), phaseFactory); err != nil {
+	startIndex, err := startPhaseIndex(opts)
+	if err != nil {
 		return err
 	}
 
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	var buildCache, launchCache Cache
+	var buildCacheName string
+	var phaseFactory PhaseFactory
+	if err := timePhase(opts, "PREPARING", func() error {
+		switch opts.CacheBackend {
+		case BindCacheBackend:
+			launchCache = cache.NewBindCache(filepath.Join(opts.BindCacheDir, "launch"), "launch", l.docker)
+			if l.cacheImage == "" {
+				buildCache = cache.NewBindCache(filepath.Join(opts.BindCacheDir, "build"), "build", l.docker)
+			}
+		default:
+			launchCache = cache.NewVolumeCache(opts.Image, "launch", l.docker, cache.WithVolumeName(opts.LaunchCacheName))
+			if l.cacheImage == "" {
+				buildCache = cache.NewVolumeCache(opts.Image, "build", l.docker, cache.WithVolumeName(opts.BuildCacheName))
+			}
+		}
+		l.logger.Debugf("Using %s cache volume %s", launchCache.Type(), style.Symbol(launchCache.Name()))
+
+		if l.cacheImage != "" {
+			buildCacheName = l.cacheImage
+			l.logger.Debugf("Using cache image %s", style.Symbol(buildCacheName))
+		} else {
+			buildCacheName = buildCache.Name()
+			l.logger.Debugf("Using %s cache volume %s", buildCache.Type(), style.Symbol(buildCacheName))
+
+			if err := clearCacheIfRequested(ctx, opts.ClearCache, opts.DryRun, buildCache, l.logger); err != nil {
+				return err
+			}
+		}
+
+		if err := clearCacheIfRequested(ctx, opts.ClearLaunchCache, opts.DryRun, launchCache, l.logger); err != nil {
+			return err
+		}
+
+		if opts.DryRun {
+			phaseFactory = NewDryRunPhaseFactory(l)
+		} else {
+			phaseFactory = NewDefaultPhaseFactory(l)
+		}
+		return nil
+	}); err != nil {
+		return timeoutError(ctx, "PREPARING", err)
+	}
+
+	if l.supportsCreator() {
+		if opts.StartPhase != "" {
+			return errors.Errorf("StartPhase %s is not supported when the builder's lifecycle runs the combined creator phase", style.Symbol(opts.StartPhase))
+		}
+		if opts.PreBuildHook != nil {
+			return errors.New("PreBuildHook is not supported when the builder's lifecycle runs the combined creator phase")
+		}
+
+		logging.PhaseStart(l.logger, "CREATING")
+		err := timePhase(opts, "CREATING", func() error {
+			return l.Create(ctx, opts.Network, opts.Volumes, opts.Image.Name(), l.additionalTags, l.runImage, opts.Publish, opts.ClearCache || opts.SkipRestore, launchCache.Name(), buildCacheName, phaseFactory)
+		})
+		if err != nil {
+			return timeoutError(ctx, "CREATING", err)
+		}
+		return nil
+	}
+
+	if startIndex <= 0 {
+		logging.PhaseStart(l.logger, "DETECTING")
+		if err := timePhase(opts, "DETECTING", func() error {
+			return l.Detect(ctx, networkOrDefault(opts.DetectNetwork, opts.Network), opts.Volumes, phaseFactory)
+		}); err != nil {
+			return timeoutError(ctx, "DETECTING", err)
+		}
+	}
+
+	if startIndex <= 1 {
+		logging.PhaseStart(l.logger, "ANALYZING")
+		if err := timePhase(opts, "ANALYZING", func() error {
+			return l.Analyze(ctx, opts.Image.Name(), buildCacheName, opts.Publish, opts.ClearCache, phaseFactory)
+		}); err != nil {
+			return timeoutError(ctx, "ANALYZING", err)
+		}
+	}
+
+	if startIndex <= 2 {
+		logging.PhaseStart(l.logger, "RESTORING")
+		if err := timePhase(opts, "RESTORING", func() error {
+			if opts.ClearCache {
+				l.logger.Info("Skipping 'restore' due to clearing cache")
+				return nil
+			}
+			if opts.SkipRestore {
+				l.logger.Info("Skipping 'restore' (disabled by options)")
+				return nil
+			}
+			return l.Restore(ctx, buildCacheName, phaseFactory)
+		}); err != nil {
+			return timeoutError(ctx, "RESTORING", err)
+		}
+	}
+
+	if startIndex <= 3 {
+		if opts.PreBuildHook != nil {
+			logging.PhaseStart(l.logger, "PRE_BUILD")
+			if err := timePhase(opts, "PRE_BUILD", func() error {
+				return l.runPreBuildHook(ctx, opts.PreBuildHook)
+			}); err != nil {
+				return timeoutError(ctx, "PRE_BUILD", err)
+			}
+		}
+
+		logging.PhaseStart(l.logger, "BUILDING")
+		if err := timePhase(opts, "BUILDING", func() error {
+			return l.Build(ctx, networkOrDefault(opts.BuildNetwork, opts.Network), opts.Volumes, phaseFactory)
+		}); err != nil {
+			return timeoutError(ctx, "BUILDING", err)
+		}
+	}
+
+	logging.PhaseStart(l.logger, "EXPORTING")
+	if err := timePhase(opts, "EXPORTING", func() error {
+		return l.Export(ctx, opts.Image.Name(), l.additionalTags, l.runImage, opts.Publish, launchCache.Name(), buildCacheName, phaseFactory)
+	}); err != nil {
+		return timeoutError(ctx, "EXPORTING", err)
+	}
+
 	return nil
 }
 
-func (l *Lifecycle) Setup(opts LifecycleOptions) {
-	l.LayersVolume = "pack-layers-" + randString(10)
+// timeoutError replaces err with a "lifecycle timed out during <phase>" error when ctx's
+// deadline is what actually caused the phase to fail, so callers see a clear explanation instead
+// of a generic context-cancelled error bubbled up from Docker.
+func timeoutError(ctx context.Context, phase string, err error) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return errors.Errorf("lifecycle timed out during %s: %s", phase, err)
+	}
+	return err
+}
+
+// pullBuilderIfNeeded pulls opts.Builder's image through the docker client according to
+// opts.PullBuilder, logging progress the same way image.Fetcher does when the pack CLI pulls
+// any other image.
+func (l *Lifecycle) pullBuilderIfNeeded(ctx context.Context, opts LifecycleOptions) error {
+	policy := opts.PullBuilder
+	if policy == "" {
+		policy = PullIfNotPresent
+	}
+	if policy == PullNever {
+		return nil
+	}
+
+	builderName := opts.Builder.Name()
+
+	if policy == PullIfNotPresent {
+		if _, _, err := l.docker.ImageInspectWithRaw(ctx, builderName); err == nil {
+			return nil
+		}
+	}
+
+	l.logger.Debugf("Pulling builder image %s", style.Symbol(builderName))
+	_, err := image.NewFetcher(l.logger, l.docker).Fetch(ctx, builderName, true, true)
+	return err
+}
+
+func (l *Lifecycle) Setup(opts LifecycleOptions) error {
+	if err := validateVolumes(opts.Volumes); err != nil {
+		return err
+	}
+
+	if opts.Publish {
+		if err := validateAdditionalTags(opts.Image, opts.AdditionalTags); err != nil {
+			return err
+		}
+		if opts.ExportToOCILayout != "" {
+			return errors.New("ExportToOCILayout cannot be used with Publish: a build can export to either a registry or an OCI layout directory, not both")
+		}
+	}
+	l.additionalTags = opts.AdditionalTags
+	l.ociLayoutDir = opts.ExportToOCILayout
+	l.orderPath = opts.OrderPath
+	l.resources = opts.Resources
+	l.processOverrides = opts.ProcessOverrides
+
+	l.logger = newLogTee(l.logger, opts.LogWriter)
+
+	if opts.ReuseLayersVolume != "" {
+		l.LayersVolume = opts.ReuseLayersVolume
+		l.ownsLayersVolume = false
+	} else {
+		l.LayersVolume = "pack-layers-" + randString(10)
+		l.ownsLayersVolume = true
+	}
 	l.AppVolume = "pack-app-" + randString(10)
 	l.appPath = opts.AppPath
+	l.additionalAppPaths = opts.AppPaths
 	l.appOnce = &sync.Once{}
 	l.builder = opts.Builder
 	l.httpProxy = opts.HTTPProxy
 	l.httpsProxy = opts.HTTPSProxy
-	l.noProxy = opts.NoProxy
+	l.noProxy = normalizeNoProxy(opts.NoProxy)
 	l.version = opts.Builder.LifecycleDescriptor().Info.Version.String()
-	l.platformAPIVersion = opts.Builder.LifecycleDescriptor().API.PlatformVersion.String()
+
+	platformAPIVersion, err := l.validateLifecycle(opts)
+	if err != nil {
+		return err
+	}
+	l.platformAPIVersion = platformAPIVersion.String()
+
+	if opts.CacheImage != "" {
+		if opts.CacheBackend == BindCacheBackend {
+			return errors.New("CacheImage cannot be used with the bind cache backend")
+		}
+		if opts.BuildCacheName != "" {
+			return errors.New("CacheImage cannot be used with BuildCacheName")
+		}
+
+		if platformAPIVersion.SupportsVersion(api.MustParse(cacheImagePlatformAPI)) {
+			l.cacheImage = opts.CacheImage
+		} else {
+			l.logger.Warn("You specified a cache image but that is not supported by this version of the platform API; using a cache volume instead")
+		}
+	}
+
 	l.DefaultProcessType = opts.DefaultProcessType
-	l.fileFilter = opts.FileFilter
+
+	if err := validateLabels(opts.Labels); err != nil {
+		return err
+	}
+	l.labels = opts.Labels
+
+	if err := validateAnnotations(opts.Annotations); err != nil {
+		return err
+	}
+	l.annotations = opts.Annotations
+
+	l.runImage = opts.RunImage
+	if opts.Image != nil {
+		l.runImage = selectRunImageMirror(opts.Image.Name(), opts.RunImage, opts.RunImageMirrors)
+	}
+
+	l.skipMissingPreviousImage = opts.SkipMissingPreviousImage
+
+	l.fileFilter, l.platformFiles, err = applyProjectDescriptor(opts.ProjectDescriptorPath, opts.FileFilter, opts.PlatformFiles)
+	if err != nil {
+		return err
+	}
+	if l.fileFilter == nil {
+		fileFilter, err := discoverBuildIgnoreFilter(opts.AppPath)
+		if err != nil {
+			return err
+		}
+		l.fileFilter = fileFilter
+	}
+
+	l.os = opts.TargetOS
+	if l.os == "" {
+		l.os = "linux"
+	}
+	l.paths = mountPathsForOS(l.os, opts.AppDirName)
+	l.keychain = authn.NewMultiKeychain(&registryAuthKeychain{auths: opts.RegistryAuth}, authn.DefaultKeychain)
+
+	l.platformOnce = &sync.Once{}
+	if len(l.platformFiles) > 0 {
+		l.PlatformVolume = "pack-platform-" + randString(10)
+	}
+
+	l.groupAdd = opts.GroupAdd
+	return nil
+}
+
+// networkOrDefault returns phaseNetwork if set, falling back to network -- used so
+// DetectNetwork/BuildNetwork can override Network for a single phase while leaving it unset to
+// inherit the lifecycle-wide default.
+func networkOrDefault(phaseNetwork, network string) string {
+	if phaseNetwork != "" {
+		return phaseNetwork
+	}
+	return network
 }
 
+// normalizeNoProxy trims whitespace around each comma-separated entry of noProxy and rewrites a
+// leading "*" wildcard (e.g. "*.internal") to the leading-dot suffix form (".internal") that
+// NO_PROXY-aware HTTP clients, including Go's net/http, match against subdomains. Without this,
+// entries copied from shells that pad commas with spaces, or written with a literal "*", would
+// fail to match and traffic intended to bypass the proxy would be routed through it instead.
+func normalizeNoProxy(noProxy string) string {
+	if noProxy == "" {
+		return ""
+	}
+
+	entries := strings.Split(noProxy, ",")
+	for i, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry != "*" {
+			entry = strings.TrimPrefix(entry, "*")
+		}
+		entries[i] = entry
+	}
+	return strings.Join(entries, ",")
+}
+
+// validateAdditionalTags checks that each of tags resolves to the same registry as image, since a
+// single EXPORTING (or CREATING) pass authenticates against, and publishes to, only one registry.
+func validateAdditionalTags(image name.Reference, tags []string) error {
+	for _, tag := range tags {
+		ref, err := name.ParseReference(tag)
+		if err != nil {
+			return errors.Wrapf(err, "invalid additional tag %s", style.Symbol(tag))
+		}
+
+		if ref.Context().RegistryStr() != image.Context().RegistryStr() {
+			return errors.Errorf("additional tag %s must be in the same registry as %s", style.Symbol(tag), style.Symbol(image.Name()))
+		}
+	}
+	return nil
+}
+
+// validateVolumes checks that each entry in volumes is a parseable bind mount spec, that its
+// host path exists, and that its container path is absolute. It returns a descriptive error
+// naming the first invalid entry, so a bad --volume spec is caught here rather than surfacing as
+// an opaque Docker error after the cache has already been created and a phase has started.
+func validateVolumes(volumes []string) error {
+	parser := mounts.NewParser(mounts.OSLinux)
+	for _, v := range volumes {
+		volume, err := parser.ParseMountRaw(v, "")
+		if err != nil {
+			return errors.Wrapf(err, "invalid volume %s", style.Symbol(v))
+		}
+
+		if _, err := os.Stat(volume.Spec.Source); err != nil {
+			return errors.Wrapf(err, "invalid volume %s: host path does not exist", style.Symbol(v))
+		}
+
+		if !path.IsAbs(volume.Destination) {
+			return errors.Errorf("invalid volume %s: container path %s must be absolute", style.Symbol(v), style.Symbol(volume.Destination))
+		}
+	}
+	return nil
+}
+
+// validateLifecycle checks opts.Builder's lifecycle version against
+// minimumSupportedLifecycleVersion and negotiates a Platform API version with it, returning an
+// actionable error immediately if either check fails rather than letting an unsupported builder
+// run until some later phase trips over the incompatibility.
+func (l *Lifecycle) validateLifecycle(opts LifecycleOptions) (*api.Version, error) {
+	descriptor := opts.Builder.LifecycleDescriptor()
+
+	lifecycleVersion, err := semver.NewVersion(descriptor.Info.Version.String())
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing lifecycle version %s", style.Symbol(descriptor.Info.Version.String()))
+	}
+	if lifecycleVersion.LessThan(minimumSupportedLifecycleVersion) {
+		return nil, errors.Errorf(
+			"builder %s has lifecycle version %s which is no longer supported; the minimum supported lifecycle version is %s",
+			style.Symbol(opts.Builder.Name()),
+			style.Symbol(descriptor.Info.Version.String()),
+			style.Symbol(minimumSupportedLifecycleVersion.String()),
+		)
+	}
+
+	platformAPIVersion, err := negotiatePlatformAPIVersion(descriptor.API.PlatformVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "negotiating Platform API version with builder %s", style.Symbol(opts.Builder.Name()))
+	}
+	return platformAPIVersion, nil
+}
+
+// negotiatePlatformAPIVersion returns the highest Platform API version that both pack
+// (SupportedPlatformAPIVersions) and the builder's lifecycle can speak. lifecycleVersion is
+// considered to support a pack version if LifecycleAPI.SupportsVersion reports it does, which
+// allows a newer lifecycle to serve an older, still-compatible Platform API. An error is returned
+// only when there is no overlap at all.
+func negotiatePlatformAPIVersion(lifecycleVersion *api.Version) (*api.Version, error) {
+	var best *api.Version
+	for _, v := range SupportedPlatformAPIVersions {
+		candidate := api.MustParse(v)
+		if !lifecycleVersion.SupportsVersion(candidate) {
+			continue
+		}
+		if best == nil || candidate.Compare(best) > 0 {
+			best = candidate
+		}
+	}
+
+	if best == nil {
+		return nil, errors.Errorf(
+			"unable to negotiate Platform API version: pack supports %s, builder's lifecycle supports %s",
+			style.Symbol(strings.Join(SupportedPlatformAPIVersions, ", ")),
+			style.Symbol(lifecycleVersion.String()),
+		)
+	}
+
+	return best, nil
+}
+
+const (
+	volumeRemoveRetries    = 5
+	volumeRemoveRetryDelay = 100 * time.Millisecond
+)
+
 func (l *Lifecycle) Cleanup() error {
-	var reterr error
-	if err := l.docker.VolumeRemove(context.Background(), l.LayersVolume, true); err != nil {
-		reterr = errors.Wrapf(err, "failed to clean up layers volume %s", l.LayersVolume)
+	var errs []error
+	if l.ownsLayersVolume {
+		if err := removeVolumeWithRetry(context.Background(), l.docker, l.LayersVolume); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to clean up layers volume %s", l.LayersVolume))
+		}
 	}
-	if err := l.docker.VolumeRemove(context.Background(), l.AppVolume, true); err != nil {
-		reterr = errors.Wrapf(err, "failed to clean up app volume %s", l.AppVolume)
+	if err := removeVolumeWithRetry(context.Background(), l.docker, l.AppVolume); err != nil {
+		errs = append(errs, errors.Wrapf(err, "failed to clean up app volume %s", l.AppVolume))
 	}
-	return reterr
+	if l.PlatformVolume != "" {
+		if err := removeVolumeWithRetry(context.Background(), l.docker, l.PlatformVolume); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to clean up platform volume %s", l.PlatformVolume))
+		}
+	}
+	if l.lifecycleVolume != "" {
+		if err := removeVolumeWithRetry(context.Background(), l.docker, l.lifecycleVolume); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to clean up lifecycle volume %s", l.lifecycleVolume))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return &CleanupError{Errs: errs}
 }
 
+// FailedPhaseError is returned by a phase's Run method when its container exits with a non-zero
+// status code. Name is the phase's name (e.g. "detector") and ExitCode is the container's exit
+// status, letting callers type-assert and branch on well-known lifecycle exit codes -- for
+// example, the detector's "no buildpack matched" code -- rather than treating every failure the
+// same way.
+type FailedPhaseError struct {
+	Name     string
+	ExitCode int
+}
+
+func (e *FailedPhaseError) Error() string {
+	return fmt.Sprintf("failed with status code: %d", e.ExitCode)
+}
+
+// CleanupError is returned by Lifecycle.Cleanup when one or more volumes could not be removed.
+// Errs preserves every failure (rather than just the last one) so callers can inspect which
+// volumes were left behind.
+type CleanupError struct {
+	Errs []error
+}
+
+func (e *CleanupError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// removeVolumeWithRetry removes a Docker volume, retrying with exponential backoff to ride out
+// transient "volume is in use" errors from a container that hasn't finished tearing down yet.
+func removeVolumeWithRetry(ctx context.Context, docker client.CommonAPIClient, volumeName string) error {
+	var err error
+	delay := volumeRemoveRetryDelay
+	for attempt := 0; attempt < volumeRemoveRetries; attempt++ {
+		if err = docker.VolumeRemove(ctx, volumeName, true); err == nil {
+			return nil
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+	return err
+}
+
+// randString returns a cryptographically unpredictable string of n lowercase letters, suitable
+// for generating volume and container names that must not collide across concurrently running
+// pack processes.
 func randString(n int) string {
 	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
 	for i := range b {
-		b[i] = 'a' + byte(rand.Intn(26))
+		b[i] = 'a' + b[i]%26
 	}
 	return string(b)
 }
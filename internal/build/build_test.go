@@ -0,0 +1,507 @@
+package build_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/docker/docker/client"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/api"
+	"github.com/buildpacks/pack/internal/build"
+	"github.com/buildpacks/pack/internal/build/fakes"
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCleanupError(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "CleanupError", testCleanupError, spec.Report(report.Terminal{}))
+}
+
+func testCleanupError(t *testing.T, when spec.G, it spec.S) {
+	when("#Error", func() {
+		it("joins every failure's message", func() {
+			err := &build.CleanupError{Errs: []error{
+				errors.New("failed to clean up layers volume pack-layers-abc"),
+				errors.New("failed to clean up app volume pack-app-xyz"),
+			}}
+
+			h.AssertEq(t, err.Error(), "failed to clean up layers volume pack-layers-abc; failed to clean up app volume pack-app-xyz")
+		})
+	})
+}
+
+func TestFailedPhaseError(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "FailedPhaseError", testFailedPhaseError, spec.Report(report.Terminal{}))
+}
+
+func testFailedPhaseError(t *testing.T, when spec.G, it spec.S) {
+	when("#Error", func() {
+		it("includes the container's exit code", func() {
+			err := &build.FailedPhaseError{Name: "detector", ExitCode: 100}
+
+			h.AssertEq(t, err.Error(), "failed with status code: 100")
+		})
+	})
+}
+
+func TestSetupGeneratesUniqueVolumeNames(t *testing.T) {
+	fakeBuilder, err := fakes.NewFakeBuilder()
+	h.AssertNil(t, err)
+
+	const concurrency = 100
+	names := make(chan string, concurrency*2)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lifecycle := build.NewLifecycle(nil, nil)
+			if err := lifecycle.Setup(build.LifecycleOptions{Builder: fakeBuilder}); err != nil {
+				t.Error(err)
+				return
+			}
+			names <- lifecycle.LayersVolume
+			names <- lifecycle.AppVolume
+		}()
+	}
+	wg.Wait()
+	close(names)
+
+	seen := map[string]bool{}
+	for name := range names {
+		if seen[name] {
+			t.Fatalf("expected unique volume names, but %s was generated more than once", name)
+		}
+		seen[name] = true
+	}
+}
+
+func TestSetupNegotiatesPlatformAPIVersion(t *testing.T) {
+	originalSupported := build.SupportedPlatformAPIVersions
+	defer func() { build.SupportedPlatformAPIVersions = originalSupported }()
+
+	for _, tc := range []struct {
+		name             string
+		supported        []string
+		builderVersion   string
+		expectedVersion  string
+		expectedErrorMsg string
+	}{
+		{
+			name:            "overlapping versions picks the highest pack also supports",
+			supported:       []string{"0.2", "0.3"},
+			builderVersion:  "0.3",
+			expectedVersion: "0.3",
+		},
+		{
+			name:            "overlapping versions with only an older match",
+			supported:       []string{"0.2", "0.3"},
+			builderVersion:  "0.2",
+			expectedVersion: "0.2",
+		},
+		{
+			name:            "builder's lifecycle is backwards-compatible with an older pack version",
+			supported:       []string{"1.1", "1.2"},
+			builderVersion:  "1.3",
+			expectedVersion: "1.2",
+		},
+		{
+			name:             "disjoint versions fail to negotiate",
+			supported:        []string{"0.2", "0.3"},
+			builderVersion:   "0.4",
+			expectedErrorMsg: "unable to negotiate Platform API version",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			build.SupportedPlatformAPIVersions = tc.supported
+
+			platformAPIVersion, err := api.NewVersion(tc.builderVersion)
+			h.AssertNil(t, err)
+			fakeBuilder, err := fakes.NewFakeBuilder(fakes.WithPlatformVersion(platformAPIVersion))
+			h.AssertNil(t, err)
+
+			lifecycle := build.NewLifecycle(nil, nil)
+			err = lifecycle.Setup(build.LifecycleOptions{Builder: fakeBuilder})
+
+			if tc.expectedErrorMsg != "" {
+				h.AssertError(t, err, tc.expectedErrorMsg)
+				return
+			}
+			h.AssertNil(t, err)
+			h.AssertEq(t, lifecycle.PlatformAPIVersion(), tc.expectedVersion)
+		})
+	}
+}
+
+func TestSetupCacheImage(t *testing.T) {
+	t.Run("conflicts with the bind cache backend", func(t *testing.T) {
+		fakeBuilder, err := fakes.NewFakeBuilder()
+		h.AssertNil(t, err)
+
+		lifecycle := build.NewLifecycle(nil, nil)
+		err = lifecycle.Setup(build.LifecycleOptions{
+			Builder:      fakeBuilder,
+			CacheImage:   "some-registry.io/some/cache",
+			CacheBackend: build.BindCacheBackend,
+		})
+		h.AssertError(t, err, "CacheImage cannot be used with the bind cache backend")
+	})
+
+	t.Run("conflicts with BuildCacheName", func(t *testing.T) {
+		fakeBuilder, err := fakes.NewFakeBuilder()
+		h.AssertNil(t, err)
+
+		lifecycle := build.NewLifecycle(nil, nil)
+		err = lifecycle.Setup(build.LifecycleOptions{
+			Builder:        fakeBuilder,
+			CacheImage:     "some-registry.io/some/cache",
+			BuildCacheName: "some-build-cache",
+		})
+		h.AssertError(t, err, "CacheImage cannot be used with BuildCacheName")
+	})
+
+	t.Run("platform API supports cache images", func(t *testing.T) {
+		platformAPIVersion, err := api.NewVersion("0.3")
+		h.AssertNil(t, err)
+		fakeBuilder, err := fakes.NewFakeBuilder(fakes.WithPlatformVersion(platformAPIVersion))
+		h.AssertNil(t, err)
+
+		lifecycle := build.NewLifecycle(nil, nil)
+		err = lifecycle.Setup(build.LifecycleOptions{
+			Builder:    fakeBuilder,
+			CacheImage: "some-registry.io/some/cache",
+		})
+		h.AssertNil(t, err)
+	})
+
+	t.Run("platform API does not support cache images", func(t *testing.T) {
+		platformAPIVersion, err := api.NewVersion("0.2")
+		h.AssertNil(t, err)
+		fakeBuilder, err := fakes.NewFakeBuilder(fakes.WithPlatformVersion(platformAPIVersion))
+		h.AssertNil(t, err)
+
+		var outBuf bytes.Buffer
+		logger := ilogging.NewLogWithWriters(&outBuf, &outBuf)
+
+		lifecycle := build.NewLifecycle(nil, logger)
+		err = lifecycle.Setup(build.LifecycleOptions{
+			Builder:    fakeBuilder,
+			CacheImage: "some-registry.io/some/cache",
+		})
+		h.AssertNil(t, err)
+		h.AssertContains(t, outBuf.String(), "You specified a cache image but that is not supported by this version of the platform API")
+	})
+}
+
+func TestSetupLabels(t *testing.T) {
+	t.Run("rejects a key that isn't reverse-DNS", func(t *testing.T) {
+		fakeBuilder, err := fakes.NewFakeBuilder()
+		h.AssertNil(t, err)
+
+		lifecycle := build.NewLifecycle(nil, nil)
+		err = lifecycle.Setup(build.LifecycleOptions{
+			Builder: fakeBuilder,
+			Labels:  map[string]string{"git-sha": "abcd123"},
+		})
+		h.AssertError(t, err, "keys must follow the reverse-DNS convention")
+	})
+
+	t.Run("rejects a key in the io.buildpacks. namespace", func(t *testing.T) {
+		fakeBuilder, err := fakes.NewFakeBuilder()
+		h.AssertNil(t, err)
+
+		lifecycle := build.NewLifecycle(nil, nil)
+		err = lifecycle.Setup(build.LifecycleOptions{
+			Builder: fakeBuilder,
+			Labels:  map[string]string{"io.buildpacks.some-label": "some-value"},
+		})
+		h.AssertError(t, err, "namespace is reserved for the lifecycle")
+	})
+
+	t.Run("accepts a reverse-DNS key outside the reserved namespace", func(t *testing.T) {
+		fakeBuilder, err := fakes.NewFakeBuilder()
+		h.AssertNil(t, err)
+
+		lifecycle := build.NewLifecycle(nil, nil)
+		err = lifecycle.Setup(build.LifecycleOptions{
+			Builder: fakeBuilder,
+			Labels:  map[string]string{"com.example.git-sha": "abcd123"},
+		})
+		h.AssertNil(t, err)
+	})
+}
+
+func TestSetupAnnotations(t *testing.T) {
+	t.Run("rejects a key that isn't reverse-DNS", func(t *testing.T) {
+		fakeBuilder, err := fakes.NewFakeBuilder()
+		h.AssertNil(t, err)
+
+		lifecycle := build.NewLifecycle(nil, nil)
+		err = lifecycle.Setup(build.LifecycleOptions{
+			Builder:     fakeBuilder,
+			Annotations: map[string]string{"source": "https://example.com/some/repo"},
+		})
+		h.AssertError(t, err, "keys must follow the reverse-DNS convention")
+	})
+
+	t.Run("rejects a key in the io.buildpacks. namespace", func(t *testing.T) {
+		fakeBuilder, err := fakes.NewFakeBuilder()
+		h.AssertNil(t, err)
+
+		lifecycle := build.NewLifecycle(nil, nil)
+		err = lifecycle.Setup(build.LifecycleOptions{
+			Builder:     fakeBuilder,
+			Annotations: map[string]string{"io.buildpacks.some-annotation": "some-value"},
+		})
+		h.AssertError(t, err, "namespace is reserved for the lifecycle")
+	})
+
+	t.Run("accepts a reverse-DNS key outside the reserved namespace", func(t *testing.T) {
+		fakeBuilder, err := fakes.NewFakeBuilder()
+		h.AssertNil(t, err)
+
+		lifecycle := build.NewLifecycle(nil, nil)
+		err = lifecycle.Setup(build.LifecycleOptions{
+			Builder:     fakeBuilder,
+			Annotations: map[string]string{"org.opencontainers.image.source": "https://example.com/some/repo"},
+		})
+		h.AssertNil(t, err)
+	})
+}
+
+func TestAnalyzeSkipMissingPreviousImage(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	fakeBuilder, err := fakes.NewFakeBuilder()
+	h.AssertNil(t, err)
+
+	t.Run("defaults to off, so Analyze never checks for a previous image", func(t *testing.T) {
+		lifecycle := build.NewLifecycle(nil, ilogging.NewLogWithWriters(ioutil.Discard, ioutil.Discard))
+		h.AssertNil(t, lifecycle.Setup(build.LifecycleOptions{Builder: fakeBuilder}))
+
+		fakePhaseFactory := fakes.NewFakePhaseFactory()
+		err := lifecycle.Analyze(context.Background(), "some-repo-name", "some-cache-name", false, false, fakePhaseFactory)
+		h.AssertNil(t, err)
+	})
+
+	t.Run("when set, surfaces a genuine error instead of silently proceeding", func(t *testing.T) {
+		unreachableDocker, err := client.NewClientWithOpts(client.WithHost("tcp://127.0.0.1:1"))
+		h.AssertNil(t, err)
+
+		lifecycle := build.NewLifecycle(unreachableDocker, ilogging.NewLogWithWriters(ioutil.Discard, ioutil.Discard))
+		h.AssertNil(t, lifecycle.Setup(build.LifecycleOptions{
+			Builder:                  fakeBuilder,
+			SkipMissingPreviousImage: true,
+		}))
+
+		fakePhaseFactory := fakes.NewFakePhaseFactory()
+		err = lifecycle.Analyze(context.Background(), "some-repo-name", "some-cache-name", false, false, fakePhaseFactory)
+		h.AssertError(t, err, "determining whether 'some-repo-name' already exists")
+	})
+}
+
+func TestSetupRejectsUnsupportedLifecycleVersion(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		lifecycleVersion string
+		expectedErrorMsg string
+	}{
+		{
+			name:             "lifecycle version is older than the minimum supported version",
+			lifecycleVersion: "0.4.0",
+			expectedErrorMsg: "which is no longer supported",
+		},
+		{
+			name:             "lifecycle version meets the minimum supported version",
+			lifecycleVersion: "0.5.0",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			lifecycleVersion, err := semver.NewVersion(tc.lifecycleVersion)
+			h.AssertNil(t, err)
+			fakeBuilder, err := fakes.NewFakeBuilder(fakes.WithLifecycleVersion(lifecycleVersion))
+			h.AssertNil(t, err)
+
+			lifecycle := build.NewLifecycle(nil, nil)
+			err = lifecycle.Setup(build.LifecycleOptions{Builder: fakeBuilder})
+
+			if tc.expectedErrorMsg != "" {
+				h.AssertError(t, err, tc.expectedErrorMsg)
+				return
+			}
+			h.AssertNil(t, err)
+		})
+	}
+}
+
+func TestSetupValidatesVolumes(t *testing.T) {
+	fakeBuilder, err := fakes.NewFakeBuilder()
+	h.AssertNil(t, err)
+
+	existingDir, err := ioutil.TempDir("", "pack-test-volume")
+	h.AssertNil(t, err)
+	defer os.RemoveAll(existingDir)
+
+	for _, tc := range []struct {
+		name             string
+		volumes          []string
+		expectedErrorMsg string
+	}{
+		{
+			name:    "host path exists and container path is absolute",
+			volumes: []string{existingDir + ":/platform/data"},
+		},
+		{
+			name:             "host path does not exist",
+			volumes:          []string{"/path/does/not/exist:/platform/data"},
+			expectedErrorMsg: "invalid volume",
+		},
+		{
+			name:             "volume spec is malformed",
+			volumes:          []string{":::"},
+			expectedErrorMsg: "invalid volume",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			lifecycle := build.NewLifecycle(nil, nil)
+			err := lifecycle.Setup(build.LifecycleOptions{Builder: fakeBuilder, Volumes: tc.volumes})
+
+			if tc.expectedErrorMsg != "" {
+				h.AssertError(t, err, tc.expectedErrorMsg)
+				return
+			}
+			h.AssertNil(t, err)
+		})
+	}
+}
+
+func TestSetupReusesLayersVolume(t *testing.T) {
+	fakeBuilder, err := fakes.NewFakeBuilder()
+	h.AssertNil(t, err)
+
+	t.Run("ReuseLayersVolume is unset", func(t *testing.T) {
+		lifecycle := build.NewLifecycle(nil, nil)
+		err := lifecycle.Setup(build.LifecycleOptions{Builder: fakeBuilder})
+		h.AssertNil(t, err)
+
+		h.AssertContains(t, lifecycle.LayersVolume, "pack-layers-")
+	})
+
+	t.Run("ReuseLayersVolume is set", func(t *testing.T) {
+		lifecycle := build.NewLifecycle(nil, nil)
+		err := lifecycle.Setup(build.LifecycleOptions{Builder: fakeBuilder, ReuseLayersVolume: "some-existing-volume"})
+		h.AssertNil(t, err)
+
+		h.AssertEq(t, lifecycle.LayersVolume, "some-existing-volume")
+	})
+}
+
+func TestSetupPlatformVolume(t *testing.T) {
+	fakeBuilder, err := fakes.NewFakeBuilder()
+	h.AssertNil(t, err)
+
+	t.Run("PlatformFiles is unset", func(t *testing.T) {
+		lifecycle := build.NewLifecycle(nil, nil)
+		err := lifecycle.Setup(build.LifecycleOptions{Builder: fakeBuilder})
+		h.AssertNil(t, err)
+
+		h.AssertEq(t, lifecycle.PlatformVolume, "")
+	})
+
+	t.Run("PlatformFiles is set", func(t *testing.T) {
+		lifecycle := build.NewLifecycle(nil, nil)
+		err := lifecycle.Setup(build.LifecycleOptions{
+			Builder:       fakeBuilder,
+			PlatformFiles: map[string][]byte{"project-metadata.toml": []byte("some-contents")},
+		})
+		h.AssertNil(t, err)
+
+		h.AssertContains(t, lifecycle.PlatformVolume, "pack-platform-")
+	})
+}
+
+func TestSetupAdditionalTags(t *testing.T) {
+	fakeBuilder, err := fakes.NewFakeBuilder()
+	h.AssertNil(t, err)
+
+	imageRef, err := name.ParseReference("index.docker.io/some/image")
+	h.AssertNil(t, err)
+
+	t.Run("Publish is false", func(t *testing.T) {
+		lifecycle := build.NewLifecycle(nil, nil)
+		err := lifecycle.Setup(build.LifecycleOptions{
+			Builder:        fakeBuilder,
+			Image:          imageRef,
+			AdditionalTags: []string{"registry.example.com/some/image:latest"},
+		})
+		h.AssertNil(t, err)
+	})
+
+	t.Run("Publish is true", func(t *testing.T) {
+		t.Run("additional tags share Image's registry", func(t *testing.T) {
+			lifecycle := build.NewLifecycle(nil, nil)
+			err := lifecycle.Setup(build.LifecycleOptions{
+				Builder:        fakeBuilder,
+				Image:          imageRef,
+				Publish:        true,
+				AdditionalTags: []string{"index.docker.io/some/image:latest"},
+			})
+			h.AssertNil(t, err)
+		})
+
+		t.Run("an additional tag names a different registry", func(t *testing.T) {
+			lifecycle := build.NewLifecycle(nil, nil)
+			err := lifecycle.Setup(build.LifecycleOptions{
+				Builder:        fakeBuilder,
+				Image:          imageRef,
+				Publish:        true,
+				AdditionalTags: []string{"registry.example.com/some/image:latest"},
+			})
+			h.AssertError(t, err, "must be in the same registry as")
+		})
+	})
+}
+
+func TestSetupExportToOCILayout(t *testing.T) {
+	fakeBuilder, err := fakes.NewFakeBuilder()
+	h.AssertNil(t, err)
+
+	t.Run("Publish is false", func(t *testing.T) {
+		lifecycle := build.NewLifecycle(nil, nil)
+		err := lifecycle.Setup(build.LifecycleOptions{
+			Builder:           fakeBuilder,
+			ExportToOCILayout: "/some/layout-dir",
+		})
+		h.AssertNil(t, err)
+	})
+
+	t.Run("Publish is true", func(t *testing.T) {
+		lifecycle := build.NewLifecycle(nil, nil)
+		err := lifecycle.Setup(build.LifecycleOptions{
+			Builder:           fakeBuilder,
+			Publish:           true,
+			ExportToOCILayout: "/some/layout-dir",
+		})
+		h.AssertError(t, err, "ExportToOCILayout cannot be used with Publish")
+	})
+}
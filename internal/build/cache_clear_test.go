@@ -0,0 +1,80 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+type fakeCache struct {
+	name           string
+	kind           string
+	clearErr       error
+	clearCallCount int
+}
+
+func (c *fakeCache) Name() string { return c.name }
+func (c *fakeCache) Type() string { return c.kind }
+func (c *fakeCache) Clear(ctx context.Context) error {
+	c.clearCallCount++
+	return c.clearErr
+}
+
+func TestClearCacheIfRequested(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "clearCacheIfRequested", testClearCacheIfRequested, spec.Report(report.Terminal{}))
+}
+
+func testClearCacheIfRequested(t *testing.T, when spec.G, it spec.S) {
+	var (
+		c      *fakeCache
+		logger *ilogging.LogWithWriters
+		outBuf bytes.Buffer
+	)
+
+	it.Before(func() {
+		c = &fakeCache{name: "some-cache", kind: "build"}
+		logger = ilogging.NewLogWithWriters(&outBuf, &outBuf)
+	})
+
+	when("clear is false", func() {
+		it("does nothing, regardless of dryRun", func() {
+			h.AssertNil(t, clearCacheIfRequested(context.Background(), false, false, c, logger))
+			h.AssertNil(t, clearCacheIfRequested(context.Background(), false, true, c, logger))
+			h.AssertEq(t, c.clearCallCount, 0)
+		})
+	})
+
+	when("clear is true", func() {
+		when("dryRun is true", func() {
+			it("logs without clearing the cache", func() {
+				h.AssertNil(t, clearCacheIfRequested(context.Background(), true, true, c, logger))
+				h.AssertEq(t, c.clearCallCount, 0)
+			})
+		})
+
+		when("dryRun is false", func() {
+			it("clears the cache", func() {
+				h.AssertNil(t, clearCacheIfRequested(context.Background(), true, false, c, logger))
+				h.AssertEq(t, c.clearCallCount, 1)
+			})
+
+			it("returns a wrapped error naming the cache's type when Clear fails", func() {
+				c.clearErr = errors.New("some clear error")
+
+				err := clearCacheIfRequested(context.Background(), true, false, c, logger)
+				h.AssertError(t, err, "clearing build cache")
+			})
+		})
+	})
+}
@@ -0,0 +1,67 @@
+package build
+
+import (
+	"context"
+	"strings"
+
+	dcontainer "github.com/docker/docker/api/types/container"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
+)
+
+// dryRunPhase logs the container configuration a phase would have used and returns immediately,
+// without creating a container or touching the cache.
+type dryRunPhase struct {
+	name     string
+	logger   logging.Logger
+	ctrConf  *dcontainer.Config
+	hostConf *dcontainer.HostConfig
+}
+
+func (p *dryRunPhase) Run(ctx context.Context) error {
+	p.logger.Infof("Dry run: %s", style.Symbol(p.name))
+	p.logger.Infof("  image: %s", style.Symbol(p.ctrConf.Image))
+	p.logger.Infof("  command: %s", strings.Join([]string(p.ctrConf.Cmd), " "))
+	if len(p.ctrConf.Env) > 0 {
+		p.logger.Infof("  env: %s", strings.Join(p.ctrConf.Env, ", "))
+	}
+	if len(p.hostConf.Binds) > 0 {
+		p.logger.Infof("  binds: %s", strings.Join(p.hostConf.Binds, ", "))
+	}
+	if p.hostConf.NetworkMode != "" {
+		p.logger.Infof("  network: %s", p.hostConf.NetworkMode)
+	}
+	return nil
+}
+
+func (p *dryRunPhase) Cleanup() error {
+	return nil
+}
+
+func (p *dryRunPhase) Digest() string {
+	return ""
+}
+
+func (p *dryRunPhase) ProcessTypes() []string {
+	return nil
+}
+
+// DryRunPhaseFactory produces phases that log their container configuration instead of running
+// it, for use when LifecycleOptions.DryRun is set.
+type DryRunPhaseFactory struct {
+	lifecycle *Lifecycle
+}
+
+func NewDryRunPhaseFactory(lifecycle *Lifecycle) *DryRunPhaseFactory {
+	return &DryRunPhaseFactory{lifecycle: lifecycle}
+}
+
+func (m *DryRunPhaseFactory) New(provider *PhaseConfigProvider) RunnerCleaner {
+	return &dryRunPhase{
+		name:     provider.Name(),
+		logger:   m.lifecycle.logger,
+		ctrConf:  provider.ContainerConfig(),
+		hostConf: provider.HostConfig(),
+	}
+}
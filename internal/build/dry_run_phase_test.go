@@ -0,0 +1,40 @@
+package build_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/build"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDryRunPhase(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "dryRunPhase", testDryRunPhase, spec.Report(report.Terminal{}))
+}
+
+func testDryRunPhase(t *testing.T, when spec.G, it spec.S) {
+	when("#Run", func() {
+		it("logs the container configuration instead of starting a container", func() {
+			lifecycle := fakeLifecycle(t, false)
+
+			configProvider := build.NewPhaseConfigProvider(
+				"detector",
+				lifecycle,
+				build.WithArgs("-app", "/workspace"),
+			)
+
+			phaseFactory := build.NewDryRunPhaseFactory(lifecycle)
+			phase := phaseFactory.New(configProvider)
+
+			h.AssertNil(t, phase.Run(context.Background()))
+			h.AssertNil(t, phase.Cleanup())
+		})
+	})
+}
@@ -20,7 +20,7 @@ func NewFakeBuilder(ops ...func(*FakeBuilder)) (*FakeBuilder, error) {
 		return nil, err
 	}
 
-	platformAPIVersion, err := api.NewVersion("23.45")
+	platformAPIVersion, err := api.NewVersion("0.3")
 	if err != nil {
 		return nil, err
 	}
@@ -64,6 +64,12 @@ func WithPlatformVersion(version *api.Version) func(*FakeBuilder) {
 	}
 }
 
+func WithLifecycleVersion(version *semver.Version) func(*FakeBuilder) {
+	return func(fakeBuilder *FakeBuilder) {
+		fakeBuilder.ReturnForLifecycleDescriptor.Info.Version = &builder.Version{Version: *version}
+	}
+}
+
 func (b *FakeBuilder) Name() string {
 	return b.ReturnForName
 }
@@ -41,7 +41,9 @@ func NewFakeLifecycle(logVerbose bool, ops ...func(*build.LifecycleOptions)) (*b
 		op(&opts)
 	}
 
-	lifecycle.Setup(opts)
+	if err := lifecycle.Setup(opts); err != nil {
+		return nil, err
+	}
 	return lifecycle, nil
 }
 
@@ -3,8 +3,10 @@ package fakes
 import "context"
 
 type FakePhase struct {
-	CleanupCallCount int
-	RunCallCount     int
+	CleanupCallCount      int
+	RunCallCount          int
+	ReturnForDigest       string
+	ReturnForProcessTypes []string
 }
 
 func (p *FakePhase) Cleanup() error {
@@ -18,3 +20,11 @@ func (p *FakePhase) Run(ctx context.Context) error {
 
 	return nil
 }
+
+func (p *FakePhase) Digest() string {
+	return p.ReturnForDigest
+}
+
+func (p *FakePhase) ProcessTypes() []string {
+	return p.ReturnForProcessTypes
+}
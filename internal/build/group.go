@@ -0,0 +1,58 @@
+package build
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/BurntSushi/toml"
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/dist"
+)
+
+// DetectedBuildpacks parses the buildpack group DETECT selected, recorded as group.toml in the
+// layers volume, and returns the ids and versions of the buildpacks that participated in the
+// build. Call it against a Lifecycle whose LayersVolume is still present -- for example, one
+// Execute'd with LifecycleOptions.ReuseLayersVolume set, since Cleanup removes any layers volume
+// Execute itself created.
+func (l *Lifecycle) DetectedBuildpacks(ctx context.Context) ([]dist.BuildpackInfo, error) {
+	ctr, err := l.docker.ContainerCreate(ctx, &dcontainer.Config{
+		Image: l.builder.Name(),
+	}, &dcontainer.HostConfig{
+		Binds: []string{fmt.Sprintf("%s:%s", l.LayersVolume, l.paths.layersDir())},
+	}, nil, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create container for reading group.toml")
+	}
+	defer l.docker.ContainerRemove(context.Background(), ctr.ID, types.ContainerRemoveOptions{Force: true})
+
+	rc, _, err := l.docker.CopyFromContainer(ctx, ctr.ID, path.Join(l.paths.layersDir(), "group.toml"))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading group.toml")
+	}
+	defer rc.Close()
+
+	return parseGroup(rc)
+}
+
+// parseGroup extracts the list of buildpacks from group.toml's [[group]] array, read from r (the
+// single-file tar stream CopyFromContainer returns).
+func parseGroup(r io.Reader) ([]dist.BuildpackInfo, error) {
+	tr := tar.NewReader(r)
+	if _, err := tr.Next(); err != nil {
+		return nil, errors.Wrap(err, "reading group.toml archive")
+	}
+
+	var group struct {
+		Group []dist.BuildpackInfo `toml:"group"`
+	}
+	if _, err := toml.DecodeReader(tr, &group); err != nil {
+		return nil, errors.Wrap(err, "parsing group.toml")
+	}
+	return group.Group, nil
+}
@@ -0,0 +1,68 @@
+package build
+
+import (
+	"archive/tar"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/archive"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestParseGroup(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "parseGroup", testParseGroup, spec.Report(report.Terminal{}))
+}
+
+func testParseGroup(t *testing.T, when spec.G, it spec.S) {
+	when("group.toml lists buildpacks", func() {
+		it("returns them in order", func() {
+			groupToml := `[[group]]
+  id = "some/buildpack"
+  version = "1.2.3"
+
+[[group]]
+  id = "other/buildpack"
+  version = "4.5.6"
+`
+			reader := archive.GenerateTar(func(tw *tar.Writer) error {
+				return archive.AddFileToTar(tw, "group.toml", groupToml)
+			})
+			defer reader.Close()
+
+			buildpacks, err := parseGroup(reader)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(buildpacks), 2)
+			h.AssertEq(t, buildpacks[0].ID, "some/buildpack")
+			h.AssertEq(t, buildpacks[0].Version, "1.2.3")
+			h.AssertEq(t, buildpacks[1].ID, "other/buildpack")
+			h.AssertEq(t, buildpacks[1].Version, "4.5.6")
+		})
+	})
+
+	when("group.toml is empty", func() {
+		it("returns no buildpacks", func() {
+			reader := archive.GenerateTar(func(tw *tar.Writer) error {
+				return archive.AddFileToTar(tw, "group.toml", "")
+			})
+			defer reader.Close()
+
+			buildpacks, err := parseGroup(reader)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(buildpacks), 0)
+		})
+	})
+
+	when("the archive is malformed", func() {
+		it("returns an error", func() {
+			_, err := parseGroup(strings.NewReader("not a tar"))
+			h.AssertNotNil(t, err)
+		})
+	})
+}
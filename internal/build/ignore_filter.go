@@ -0,0 +1,50 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	ignore "github.com/sabhiram/go-gitignore"
+)
+
+// NewIgnoreFilter compiles patterns as gitignore-style rules and returns a filter suitable for
+// LifecycleOptions.FileFilter that excludes any path one of those patterns matches, so the app
+// volume upload skips it.
+func NewIgnoreFilter(patterns []string) (func(string) bool, error) {
+	ignorer, err := ignore.CompileIgnoreLines(patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(fileName string) bool {
+		return !ignorer.MatchesPath(fileName)
+	}, nil
+}
+
+// buildIgnoreFile is the name of the file discoverBuildIgnoreFilter looks for directly under an
+// app's root directory.
+const buildIgnoreFile = ".buildignore"
+
+// discoverBuildIgnoreFilter looks for a .buildignore file directly under appPath and, if found,
+// builds a FileFilter from its gitignore-style patterns via NewIgnoreFilter. It returns a nil
+// filter, not an error, when appPath isn't a directory or has no .buildignore -- there's simply
+// nothing to discover in either case.
+func discoverBuildIgnoreFilter(appPath string) (func(string) bool, error) {
+	fi, err := os.Stat(appPath)
+	if err != nil || !fi.IsDir() {
+		return nil, nil
+	}
+
+	contents, err := ioutil.ReadFile(filepath.Join(appPath, buildIgnoreFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "reading %s", buildIgnoreFile)
+	}
+
+	return NewIgnoreFilter(strings.Split(string(contents), "\n"))
+}
@@ -0,0 +1,116 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/api"
+	"github.com/buildpacks/pack/internal/builder"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+// minimalFakeBuilder is a bare-bones Builder implementation used by white-box tests in this
+// package -- fakes.FakeBuilder lives in internal/build/fakes, which itself imports this package,
+// so it can't be used from tests that are part of the build package rather than build_test.
+type minimalFakeBuilder struct{}
+
+func (minimalFakeBuilder) Name() string { return "some-builder" }
+func (minimalFakeBuilder) UID() int     { return 0 }
+func (minimalFakeBuilder) GID() int     { return 0 }
+func (minimalFakeBuilder) LifecycleDescriptor() builder.LifecycleDescriptor {
+	return builder.LifecycleDescriptor{
+		Info: builder.LifecycleInfo{Version: &builder.Version{Version: *semver.MustParse("1.0.0")}},
+		API:  builder.LifecycleAPI{PlatformVersion: api.MustParse("0.3")},
+	}
+}
+
+func TestIgnoreFilter(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "ignoreFilter", testIgnoreFilter, spec.Report(report.Terminal{}))
+}
+
+func testIgnoreFilter(t *testing.T, when spec.G, it spec.S) {
+	var appDir string
+
+	it.Before(func() {
+		var err error
+		appDir, err = ioutil.TempDir("", "pack-test-buildignore")
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(appDir))
+	})
+
+	when("#discoverBuildIgnoreFilter", func() {
+		when("app has a .buildignore", func() {
+			it.Before(func() {
+				h.AssertNil(t, ioutil.WriteFile(filepath.Join(appDir, buildIgnoreFile), []byte("secrets/\n"), 0644))
+			})
+
+			it("builds a filter that excludes the ignored patterns", func() {
+				filter, err := discoverBuildIgnoreFilter(appDir)
+				h.AssertNil(t, err)
+				h.AssertNotNil(t, filter)
+				h.AssertFalse(t, filter("secrets/api-key.txt"))
+				h.AssertTrue(t, filter("main.go"))
+			})
+		})
+
+		when("app has no .buildignore", func() {
+			it("returns a nil filter", func() {
+				filter, err := discoverBuildIgnoreFilter(appDir)
+				h.AssertNil(t, err)
+				if filter != nil {
+					t.Fatalf("expected a nil filter when no .buildignore is present")
+				}
+			})
+		})
+	})
+
+	when("Setup discovers a .buildignore", func() {
+		it.Before(func() {
+			h.AssertNil(t, ioutil.WriteFile(filepath.Join(appDir, buildIgnoreFile), []byte("secrets/\n"), 0644))
+		})
+
+		when("FileFilter is unset", func() {
+			it("uses the discovered filter", func() {
+				lifecycle := NewLifecycle(nil, nil)
+				h.AssertNil(t, lifecycle.Setup(LifecycleOptions{Builder: minimalFakeBuilder{}, AppPath: appDir}))
+
+				h.AssertNotNil(t, lifecycle.fileFilter)
+				h.AssertFalse(t, lifecycle.fileFilter("secrets/api-key.txt"))
+			})
+		})
+
+		when("FileFilter is explicitly set", func() {
+			it("takes precedence over the discovered .buildignore", func() {
+				explicitFilter := func(string) bool { return true }
+
+				lifecycle := NewLifecycle(nil, nil)
+				h.AssertNil(t, lifecycle.Setup(LifecycleOptions{Builder: minimalFakeBuilder{}, AppPath: appDir, FileFilter: explicitFilter}))
+
+				h.AssertTrue(t, lifecycle.fileFilter("secrets/api-key.txt"))
+			})
+		})
+	})
+
+	when("#NewIgnoreFilter", func() {
+		it("excludes paths matching the given patterns", func() {
+			filter, err := NewIgnoreFilter([]string{"node_modules/**"})
+			h.AssertNil(t, err)
+
+			h.AssertFalse(t, filter("node_modules/left-pad/index.js"))
+			h.AssertTrue(t, filter("src/main.js"))
+		})
+	})
+}
@@ -0,0 +1,52 @@
+package build
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// reservedLabelNamespace is the label prefix the lifecycle uses for its own build and project
+// metadata labels; custom labels aren't allowed to collide with it.
+const reservedLabelNamespace = "io.buildpacks."
+
+var labelKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9-]+(\.[a-zA-Z0-9-]+)+$`)
+
+// validateLabels ensures each label key follows the reverse-DNS convention (e.g.
+// com.example.git-sha) and doesn't fall within the reservedLabelNamespace.
+func validateLabels(labels map[string]string) error {
+	for key := range labels {
+		if !labelKeyPattern.MatchString(key) {
+			return errors.Errorf("invalid label %s: keys must follow the reverse-DNS convention (e.g. com.example.git-sha)", style.Symbol(key))
+		}
+		if strings.HasPrefix(key, reservedLabelNamespace) {
+			return errors.Errorf("invalid label %s: the %s namespace is reserved for the lifecycle", style.Symbol(key), style.Symbol(reservedLabelNamespace))
+		}
+	}
+	return nil
+}
+
+// labelArgs returns a -label key=value pair for each label, sorted by key so repeated calls
+// produce the same phase arguments.
+func labelArgs(labels map[string]string) []string {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	args := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		args = append(args, "-label", fmt.Sprintf("%s=%s", key, labels[key]))
+	}
+	return args
+}
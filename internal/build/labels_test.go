@@ -0,0 +1,37 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestLabelArgs(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "labelArgs", testLabelArgs, spec.Report(report.Terminal{}))
+}
+
+func testLabelArgs(t *testing.T, when spec.G, it spec.S) {
+	when("no labels are given", func() {
+		it("returns no args", func() {
+			h.AssertEq(t, labelArgs(nil), []string(nil))
+		})
+	})
+
+	when("labels are given", func() {
+		it("sorts them by key for deterministic output", func() {
+			labels := map[string]string{
+				"com.example.b": "2",
+				"com.example.a": "1",
+			}
+
+			h.AssertEq(t, labelArgs(labels), []string{"-label", "com.example.a=1", "-label", "com.example.b=2"})
+		})
+	})
+}
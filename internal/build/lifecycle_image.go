@@ -0,0 +1,113 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/api"
+	"github.com/buildpacks/pack/internal/container"
+	"github.com/buildpacks/pack/internal/image"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// lifecycleDir is where a phase container expects to find its lifecycle binaries.
+const lifecycleDir = "/cnb/lifecycle"
+
+// lifecyclePlatformAPIsLabel names the image label a lifecycle image uses to declare which
+// Platform API versions its binaries support.
+const lifecyclePlatformAPIsLabel = "io.buildpacks.lifecycle.apis.platform.supported"
+
+// lifecycleVolumeMountDir is where prepareLifecycleImage copies a lifecycle image's binaries to
+// inside the throwaway container used to populate l.lifecycleVolume. It deliberately isn't
+// lifecycleDir ("/cnb/lifecycle"), since binding the destination volume there would shadow the
+// very files the copy needs to read from.
+const lifecycleVolumeMountDir = "/pack-lifecycle"
+
+// prepareLifecycleImage pulls lifecycleImage through the docker client if it isn't already
+// present, validates that it declares support for the negotiated Platform API, and copies its
+// /cnb/lifecycle binaries into a fresh volume that NewPhaseConfigProvider mounts over every
+// phase container's own /cnb/lifecycle. It's a no-op when lifecycleImage is empty, leaving each
+// phase container to use Builder's embedded lifecycle as before. Call it after Setup, since it
+// depends on the Platform API negotiated there.
+func (l *Lifecycle) prepareLifecycleImage(ctx context.Context, lifecycleImage string) error {
+	if lifecycleImage == "" {
+		return nil
+	}
+
+	inspect, _, err := l.docker.ImageInspectWithRaw(ctx, lifecycleImage)
+	if err != nil {
+		l.logger.Debugf("Pulling lifecycle image %s", style.Symbol(lifecycleImage))
+		if _, err := image.NewFetcher(l.logger, l.docker).Fetch(ctx, lifecycleImage, true, true); err != nil {
+			return errors.Wrapf(err, "fetching lifecycle image %s", style.Symbol(lifecycleImage))
+		}
+
+		inspect, _, err = l.docker.ImageInspectWithRaw(ctx, lifecycleImage)
+		if err != nil {
+			return errors.Wrapf(err, "inspecting lifecycle image %s", style.Symbol(lifecycleImage))
+		}
+	}
+
+	if err := validateLifecycleImagePlatformAPI(lifecycleImage, inspect, l.platformAPIVersion); err != nil {
+		return err
+	}
+
+	l.lifecycleVolume = "pack-lifecycle-" + randString(10)
+	return l.copyLifecycleBinaries(ctx, lifecycleImage, l.lifecycleVolume)
+}
+
+// validateLifecycleImagePlatformAPI returns an error unless lifecycleImage's
+// lifecyclePlatformAPIsLabel includes platformAPIVersion.
+func validateLifecycleImagePlatformAPI(lifecycleImage string, inspect types.ImageInspect, platformAPIVersion string) error {
+	var supported string
+	if inspect.Config != nil {
+		supported = inspect.Config.Labels[lifecyclePlatformAPIsLabel]
+	}
+	if supported == "" {
+		return errors.Errorf(
+			"lifecycle image %s is missing the %s label",
+			style.Symbol(lifecycleImage),
+			style.Symbol(lifecyclePlatformAPIsLabel),
+		)
+	}
+
+	negotiated := api.MustParse(platformAPIVersion)
+	for _, v := range strings.Split(supported, ",") {
+		if api.MustParse(strings.TrimSpace(v)).Equal(negotiated) {
+			return nil
+		}
+	}
+	return errors.Errorf(
+		"lifecycle image %s does not support platform API %s (supports %s)",
+		style.Symbol(lifecycleImage),
+		style.Symbol(platformAPIVersion),
+		supported,
+	)
+}
+
+// copyLifecycleBinaries runs a throwaway container off lifecycleImage that copies its
+// /cnb/lifecycle directory into volumeName.
+func (l *Lifecycle) copyLifecycleBinaries(ctx context.Context, lifecycleImage, volumeName string) error {
+	ctr, err := l.docker.ContainerCreate(ctx, &dcontainer.Config{
+		Image:      lifecycleImage,
+		Entrypoint: []string{"/bin/cp"},
+		Cmd:        []string{"-a", lifecycleDir + "/.", lifecycleVolumeMountDir},
+	}, &dcontainer.HostConfig{
+		Binds: []string{fmt.Sprintf("%s:%s", volumeName, lifecycleVolumeMountDir)},
+	}, nil, "")
+	if err != nil {
+		return errors.Wrap(err, "failed to create container for copying lifecycle binaries")
+	}
+	defer l.docker.ContainerRemove(context.Background(), ctr.ID, types.ContainerRemoveOptions{Force: true})
+
+	var stdout, stderr bytes.Buffer
+	if err := container.Run(ctx, l.docker, ctr.ID, &stdout, &stderr); err != nil {
+		return errors.Wrapf(err, "copying binaries from lifecycle image %s: %s", style.Symbol(lifecycleImage), stderr.String())
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestValidateLifecycleImagePlatformAPI(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "validateLifecycleImagePlatformAPI", testValidateLifecycleImagePlatformAPI, spec.Report(report.Terminal{}))
+}
+
+func testValidateLifecycleImagePlatformAPI(t *testing.T, when spec.G, it spec.S) {
+	inspectWithLabel := func(value string) types.ImageInspect {
+		return types.ImageInspect{
+			Config: &dcontainer.Config{
+				Labels: map[string]string{lifecyclePlatformAPIsLabel: value},
+			},
+		}
+	}
+
+	when("the label lists the negotiated platform API", func() {
+		it("succeeds", func() {
+			err := validateLifecycleImagePlatformAPI("some-lifecycle-image", inspectWithLabel("0.2, 0.3, 0.4"), "0.3")
+			h.AssertNil(t, err)
+		})
+	})
+
+	when("the label doesn't list the negotiated platform API", func() {
+		it("returns an error", func() {
+			err := validateLifecycleImagePlatformAPI("some-lifecycle-image", inspectWithLabel("0.2, 0.3"), "0.4")
+			h.AssertNotNil(t, err)
+		})
+	})
+
+	when("the image has no label", func() {
+		it("returns an error", func() {
+			err := validateLifecycleImagePlatformAPI("some-lifecycle-image", types.ImageInspect{Config: &dcontainer.Config{}}, "0.3")
+			h.AssertNotNil(t, err)
+		})
+	})
+
+	when("the image has no config", func() {
+		it("returns an error", func() {
+			err := validateLifecycleImagePlatformAPI("some-lifecycle-image", types.ImageInspect{}, "0.3")
+			h.AssertNotNil(t, err)
+		})
+	})
+}
@@ -0,0 +1,82 @@
+package build
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/buildpacks/pack/logging"
+)
+
+// logTee wraps a logging.Logger so that everything logged through it -- including phase
+// container stdout/stderr, which phase.go retrieves via logging.GetWriterForLevel -- is also
+// written to w.
+type logTee struct {
+	logging.Logger
+	w io.Writer
+}
+
+// newLogTee returns logger unchanged if w is nil; otherwise it wraps logger so all of its
+// output is duplicated to w.
+func newLogTee(logger logging.Logger, w io.Writer) logging.Logger {
+	if w == nil {
+		return logger
+	}
+	return &logTee{Logger: logger, w: w}
+}
+
+func (t *logTee) Debug(msg string) {
+	t.Logger.Debug(msg)
+	fmt.Fprintln(t.w, msg)
+}
+
+func (t *logTee) Debugf(format string, v ...interface{}) {
+	t.Debug(fmt.Sprintf(format, v...))
+}
+
+func (t *logTee) Info(msg string) {
+	t.Logger.Info(msg)
+	fmt.Fprintln(t.w, msg)
+}
+
+func (t *logTee) Infof(format string, v ...interface{}) {
+	t.Info(fmt.Sprintf(format, v...))
+}
+
+func (t *logTee) Warn(msg string) {
+	t.Logger.Warn(msg)
+	fmt.Fprintln(t.w, msg)
+}
+
+func (t *logTee) Warnf(format string, v ...interface{}) {
+	t.Warn(fmt.Sprintf(format, v...))
+}
+
+func (t *logTee) Error(msg string) {
+	t.Logger.Error(msg)
+	fmt.Fprintln(t.w, msg)
+}
+
+func (t *logTee) Errorf(format string, v ...interface{}) {
+	t.Error(fmt.Sprintf(format, v...))
+}
+
+// PhaseStart delegates to the wrapped logger -- using its native PhaseStart if it has one -- and
+// also writes the phase name to w.
+func (t *logTee) PhaseStart(phase string) {
+	logging.PhaseStart(t.Logger, phase)
+	fmt.Fprintln(t.w, phase)
+}
+
+// SetLevel delegates to the wrapped logger if it supports level filtering; the tee itself always
+// receives everything the wrapped logger is configured to produce.
+func (t *logTee) SetLevel(level logging.Level) {
+	if settable, ok := t.Logger.(logging.WithLevel); ok {
+		settable.SetLevel(level)
+	}
+}
+
+// WriterForLevel duplicates the wrapped logger's writer for level to w, so phase container
+// output -- retrieved by phase.go via logging.GetWriterForLevel -- reaches both places.
+func (t *logTee) WriterForLevel(level logging.Level) io.Writer {
+	return io.MultiWriter(logging.GetWriterForLevel(t.Logger, level), t.w)
+}
@@ -0,0 +1,57 @@
+package build
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestLogTee(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "newLogTee", testLogTee, spec.Report(report.Terminal{}))
+}
+
+func testLogTee(t *testing.T, when spec.G, it spec.S) {
+	when("LogWriter is nil", func() {
+		it("returns the logger unchanged", func() {
+			var terminal bytes.Buffer
+			base := logging.New(&terminal)
+
+			tee := newLogTee(base, nil)
+
+			h.AssertSameInstance(t, tee, base)
+		})
+	})
+
+	when("LogWriter is set", func() {
+		it("still delivers messages to the wrapped logger", func() {
+			var terminal, file bytes.Buffer
+			base := logging.New(&terminal)
+
+			tee := newLogTee(base, &file)
+			tee.Info("hello")
+
+			h.AssertContains(t, terminal.String(), "hello")
+			h.AssertEq(t, file.String(), "hello\n")
+		})
+
+		it("duplicates phase container output to LogWriter", func() {
+			var terminal, file bytes.Buffer
+			base := logging.New(&terminal)
+
+			tee := newLogTee(base, &file)
+			w := logging.GetWriterForLevel(tee, logging.InfoLevel)
+			_, _ = w.Write([]byte("container output\n"))
+
+			h.AssertEq(t, file.String(), "container output\n")
+		})
+	})
+}
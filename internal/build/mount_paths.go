@@ -0,0 +1,63 @@
+package build
+
+import "strings"
+
+// mountPaths resolves the absolute, container-side paths the lifecycle binaries expect to find
+// their working directories at. Nested paths are always joined with the path separator
+// appropriate for os, so callers don't end up mixing `/` and `\` when building a path under
+// platformDir or another directory.
+type mountPaths struct {
+	os         string
+	appDirName string
+}
+
+// mountPathsForOS returns the mountPaths used for a container running the given OS
+// ("linux" or "windows"), mounting the app source under appDirName. An empty appDirName
+// defaults to "workspace".
+func mountPathsForOS(os, appDirName string) mountPaths {
+	if appDirName == "" {
+		appDirName = "workspace"
+	}
+	return mountPaths{os: os, appDirName: appDirName}
+}
+
+// join builds an absolute path under the container's root directory from parts, using the
+// separator appropriate for m.os.
+func (m mountPaths) join(parts ...string) string {
+	if m.os == "windows" {
+		return `c:\` + strings.Join(parts, `\`)
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+func (m mountPaths) appDir() string {
+	return m.join(m.appDirName)
+}
+
+func (m mountPaths) layersDir() string {
+	return m.join("layers")
+}
+
+func (m mountPaths) cacheDir() string {
+	return m.join("cache")
+}
+
+func (m mountPaths) launchCacheDir() string {
+	return m.join("launch-cache")
+}
+
+func (m mountPaths) platformDir() string {
+	return m.join("platform")
+}
+
+func (m mountPaths) ociLayoutDir() string {
+	return m.join("layout")
+}
+
+func (m mountPaths) sbomDir() string {
+	return m.join("layers", "sbom")
+}
+
+func (m mountPaths) orderPath() string {
+	return m.join("cnb", "order.toml")
+}
@@ -0,0 +1,62 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestMountPaths(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "mountPaths", testMountPaths, spec.Report(report.Terminal{}))
+}
+
+func testMountPaths(t *testing.T, when spec.G, it spec.S) {
+	when("os is linux", func() {
+		it("joins nested paths with forward slashes", func() {
+			paths := mountPathsForOS("linux", "")
+			h.AssertEq(t, paths.appDir(), "/workspace")
+			h.AssertEq(t, paths.layersDir(), "/layers")
+			h.AssertEq(t, paths.cacheDir(), "/cache")
+			h.AssertEq(t, paths.launchCacheDir(), "/launch-cache")
+			h.AssertEq(t, paths.platformDir(), "/platform")
+			h.AssertEq(t, paths.ociLayoutDir(), "/layout")
+			h.AssertEq(t, paths.sbomDir(), "/layers/sbom")
+			h.AssertEq(t, paths.orderPath(), "/cnb/order.toml")
+			h.AssertEq(t, paths.join("platform", "env"), "/platform/env")
+		})
+	})
+
+	when("os is windows", func() {
+		it("joins nested paths with backslashes", func() {
+			paths := mountPathsForOS("windows", "")
+			h.AssertEq(t, paths.appDir(), `c:\workspace`)
+			h.AssertEq(t, paths.layersDir(), `c:\layers`)
+			h.AssertEq(t, paths.cacheDir(), `c:\cache`)
+			h.AssertEq(t, paths.launchCacheDir(), `c:\launch-cache`)
+			h.AssertEq(t, paths.platformDir(), `c:\platform`)
+			h.AssertEq(t, paths.ociLayoutDir(), `c:\layout`)
+			h.AssertEq(t, paths.sbomDir(), `c:\layers\sbom`)
+			h.AssertEq(t, paths.orderPath(), `c:\cnb\order.toml`)
+			h.AssertEq(t, paths.join("platform", "env"), `c:\platform\env`)
+		})
+	})
+
+	when("appDirName", func() {
+		it("defaults to workspace when empty", func() {
+			paths := mountPathsForOS("linux", "")
+			h.AssertEq(t, paths.appDir(), "/workspace")
+		})
+
+		it("overrides the app directory name when given", func() {
+			paths := mountPathsForOS("linux", "src")
+			h.AssertEq(t, paths.appDir(), "/src")
+		})
+	})
+}
@@ -0,0 +1,38 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestNetworkOrDefault(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "networkOrDefault", testNetworkOrDefault, spec.Report(report.Terminal{}))
+}
+
+func testNetworkOrDefault(t *testing.T, when spec.G, it spec.S) {
+	when("the phase network is set", func() {
+		it("takes precedence over the default network", func() {
+			h.AssertEq(t, networkOrDefault("none", "host"), "none")
+		})
+	})
+
+	when("the phase network is unset", func() {
+		it("falls back to the default network", func() {
+			h.AssertEq(t, networkOrDefault("", "host"), "host")
+		})
+	})
+
+	when("neither is set", func() {
+		it("returns an empty string", func() {
+			h.AssertEq(t, networkOrDefault("", ""), "")
+		})
+	})
+}
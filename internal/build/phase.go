@@ -1,12 +1,16 @@
 package build
 
 import (
+	"archive/tar"
 	"context"
 	"io"
 	"os"
+	"path"
 	"runtime"
 	"sync"
 
+	"github.com/BurntSushi/toml"
+	"github.com/buildpacks/lifecycle/launch"
 	"github.com/docker/docker/api/types"
 	dcontainer "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
@@ -18,16 +22,26 @@ import (
 )
 
 type Phase struct {
-	name       string
-	logger     logging.Logger
-	docker     client.CommonAPIClient
-	ctrConf    *dcontainer.Config
-	hostConf   *dcontainer.HostConfig
-	ctr        dcontainer.ContainerCreateCreatedBody
-	uid, gid   int
-	appPath    string
-	appOnce    *sync.Once
-	fileFilter func(string) bool
+	name               string
+	logger             logging.Logger
+	docker             client.CommonAPIClient
+	ctrConf            *dcontainer.Config
+	hostConf           *dcontainer.HostConfig
+	ctr                dcontainer.ContainerCreateCreatedBody
+	uid, gid           int
+	appPath            string
+	additionalAppPaths []string
+	appOnce            *sync.Once
+	appDir             string
+	fileFilter         func(string) bool
+	platformDir        string
+	platformFiles      map[string][]byte
+	platformOnce       *sync.Once
+	layersDir          string
+	isExporter         bool
+	isBuilder          bool
+	digest             string
+	processTypes       []string
 }
 
 func (p *Phase) Run(ctx context.Context) error {
@@ -39,17 +53,135 @@ func (p *Phase) Run(ctx context.Context) error {
 	}
 
 	p.appOnce.Do(func() {
-		var (
-			appReader io.ReadCloser
-			clientErr error
-		)
-		appReader, err = p.createAppReader()
-		if err != nil {
-			err = errors.Wrapf(err, "create tar archive from '%s'", p.appPath)
+		for _, appPath := range p.appSourcePaths() {
+			if err = p.copyAppSource(ctx, appPath); err != nil {
+				return
+			}
+		}
+	})
+
+	if err != nil {
+		return errors.Wrapf(err, "failed to copy files to '%s' container", p.name)
+	}
+
+	if err := p.copyPlatformFiles(ctx); err != nil {
+		return errors.Wrapf(err, "failed to copy platform files to '%s' container", p.name)
+	}
+
+	if err := container.Run(
+		ctx,
+		p.docker,
+		p.ctr.ID,
+		logging.NewPrefixWriter(logging.GetWriterForLevel(p.logger, logging.InfoLevel), p.name),
+		logging.NewPrefixWriter(logging.GetWriterForLevel(p.logger, logging.ErrorLevel), p.name),
+	); err != nil {
+		if exitErr, ok := err.(*container.ExitError); ok {
+			return &FailedPhaseError{Name: p.name, ExitCode: exitErr.ExitCode}
+		}
+		return err
+	}
+
+	p.captureDigest(ctx)
+	p.captureProcessTypes(ctx)
+	return nil
+}
+
+// captureDigest reads the image digest the exporter reported in report.toml under the layers
+// directory, populating p.digest for Digest to return. It's a best-effort read: any failure --
+// report.toml not existing (this phase isn't an exporter, or the image was exported to the
+// daemon rather than a registry) or being malformed -- leaves p.digest empty rather than failing
+// the phase, since the exported image itself already succeeded by the time this runs.
+func (p *Phase) captureDigest(ctx context.Context) {
+	if !p.isExporter {
+		return
+	}
+
+	rc, _, err := p.docker.CopyFromContainer(ctx, p.ctr.ID, path.Join(p.layersDir, "report.toml"))
+	if err != nil {
+		return
+	}
+	defer rc.Close()
+
+	p.digest = parseReportDigest(rc)
+}
+
+// parseReportDigest extracts the image digest from report.toml's [image] table, read from r (the
+// single-file tar stream CopyFromContainer returns). It returns "" if r doesn't contain a valid
+// report.toml.
+func parseReportDigest(r io.Reader) string {
+	tr := tar.NewReader(r)
+	if _, err := tr.Next(); err != nil {
+		return ""
+	}
+
+	var report struct {
+		Image struct {
+			Digest string `toml:"digest"`
+		} `toml:"image"`
+	}
+	if _, err := toml.DecodeReader(tr, &report); err != nil {
+		return ""
+	}
+	return report.Image.Digest
+}
+
+// Digest returns the image digest captured by captureDigest, or "" if this phase isn't an
+// exporter or didn't publish to a registry.
+func (p *Phase) Digest() string {
+	return p.digest
+}
+
+// captureProcessTypes reads the process types the builder declared in its metadata.toml under
+// the layers directory, populating p.processTypes for ProcessTypes to return. Like
+// captureDigest, it's a best-effort read: any failure leaves p.processTypes nil rather than
+// failing the phase, since the build itself already succeeded by the time this runs.
+func (p *Phase) captureProcessTypes(ctx context.Context) {
+	if !p.isBuilder {
+		return
+	}
+
+	rc, _, err := p.docker.CopyFromContainer(ctx, p.ctr.ID, launch.GetMetadataFilePath(p.layersDir))
+	if err != nil {
+		return
+	}
+	defer rc.Close()
+
+	tr := tar.NewReader(rc)
+	if _, err := tr.Next(); err != nil {
+		return
+	}
+
+	var metadata launch.Metadata
+	if _, err := toml.DecodeReader(tr, &metadata); err != nil {
+		return
+	}
+
+	processTypes := make([]string, 0, len(metadata.Processes))
+	for _, process := range metadata.Processes {
+		processTypes = append(processTypes, process.Type)
+	}
+	p.processTypes = processTypes
+}
+
+// ProcessTypes returns the process types captured by captureProcessTypes, or nil if this phase
+// isn't a builder.
+func (p *Phase) ProcessTypes() []string {
+	return p.processTypes
+}
+
+// copyPlatformFiles copies p.platformFiles into the running container's platform directory, once
+// per Lifecycle (platformOnce is shared by every phase the Lifecycle creates), the same way Run copies app source into the app directory.
+func (p *Phase) copyPlatformFiles(ctx context.Context) error {
+	var err error
+	p.platformOnce.Do(func() {
+		if len(p.platformFiles) == 0 {
 			return
 		}
-		defer appReader.Close()
 
+		platformReader := buildPlatformFilesTar(p.platformDir, p.platformFiles)
+		defer platformReader.Close()
+
+		var clientErr error
 		doneChan := make(chan interface{})
 		pr, pw := io.Pipe()
 		go func() {
@@ -58,7 +190,7 @@ func (p *Phase) Run(ctx context.Context) error {
 		}()
 		func() {
 			defer pw.Close()
-			_, err = io.Copy(pw, appReader)
+			_, err = io.Copy(pw, platformReader)
 		}()
 
 		<-doneChan
@@ -66,26 +198,65 @@ func (p *Phase) Run(ctx context.Context) error {
 			err = clientErr
 		}
 	})
+	return err
+}
 
-	if err != nil {
-		return errors.Wrapf(err, "failed to copy files to '%s' container", p.name)
-	}
-
-	return container.Run(
-		ctx,
-		p.docker,
-		p.ctr.ID,
-		logging.NewPrefixWriter(logging.GetWriterForLevel(p.logger, logging.InfoLevel), p.name),
-		logging.NewPrefixWriter(logging.GetWriterForLevel(p.logger, logging.ErrorLevel), p.name),
-	)
+// buildPlatformFilesTar builds a tar archive containing files rooted at platformDir, so
+// copyPlatformFiles can hand it straight to CopyToContainer.
+func buildPlatformFilesTar(platformDir string, files map[string][]byte) io.ReadCloser {
+	return archive.GenerateTar(func(tw *tar.Writer) error {
+		for name, contents := range files {
+			if err := archive.AddFileToTar(tw, path.Join(platformDir, name), string(contents)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (p *Phase) Cleanup() error {
 	return p.docker.ContainerRemove(context.Background(), p.ctr.ID, types.ContainerRemoveOptions{Force: true})
 }
 
-func (p *Phase) createAppReader() (io.ReadCloser, error) {
-	fi, err := os.Stat(p.appPath)
+// appSourcePaths returns every app source Run copies into the container, in the order they
+// should be applied: p.appPath first, then p.additionalAppPaths. Later sources are copied after
+// earlier ones, so a file present in more than one source ends up with the content from the last
+// source that has it; files unique to an earlier source are unaffected.
+func (p *Phase) appSourcePaths() []string {
+	return append([]string{p.appPath}, p.additionalAppPaths...)
+}
+
+// copyAppSource tars appPath and streams it into the running container at p.appDir. Calling it
+// more than once for overlapping paths is how multiple app sources are merged: Docker overwrites
+// a file already present at a given path and leaves files it doesn't mention untouched.
+func (p *Phase) copyAppSource(ctx context.Context, appPath string) error {
+	appReader, err := p.createAppReader(appPath)
+	if err != nil {
+		return errors.Wrapf(err, "create tar archive from '%s'", appPath)
+	}
+	defer appReader.Close()
+
+	var clientErr error
+	doneChan := make(chan interface{})
+	pr, pw := io.Pipe()
+	go func() {
+		clientErr = p.docker.CopyToContainer(ctx, p.ctr.ID, "/", pr, types.CopyToContainerOptions{})
+		close(doneChan)
+	}()
+	func() {
+		defer pw.Close()
+		_, err = io.Copy(pw, appReader)
+	}()
+
+	<-doneChan
+	if err == nil {
+		err = clientErr
+	}
+	return err
+}
+
+func (p *Phase) createAppReader(appPath string) (io.ReadCloser, error) {
+	fi, err := os.Stat(appPath)
 	if err != nil {
 		return nil, err
 	}
@@ -96,8 +267,22 @@ func (p *Phase) createAppReader() (io.ReadCloser, error) {
 			mode = 0777
 		}
 
-		return archive.ReadDirAsTar(p.appPath, appDir, p.uid, p.gid, mode, false, p.fileFilter), nil
+		return archive.ReadDirAsTar(appPath, p.appDir, p.uid, p.gid, mode, false, p.fileFilter), nil
+	}
+
+	fh, err := os.Open(appPath)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	isZip, err := archive.IsZip(fh)
+	if err != nil {
+		return nil, err
+	}
+	if isZip {
+		return archive.ReadZipAsTar(appPath, p.appDir, p.uid, p.gid, -1, false, p.fileFilter), nil
 	}
 
-	return archive.ReadZipAsTar(p.appPath, appDir, p.uid, p.gid, -1, false, p.fileFilter), nil
+	return archive.ReadTarAsTar(appPath, p.appDir, p.uid, p.gid, -1, false, p.fileFilter), nil
 }
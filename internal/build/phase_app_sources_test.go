@@ -0,0 +1,100 @@
+package build
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestAppSourcePaths(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "appSourcePaths", testAppSourcePaths, spec.Report(report.Terminal{}))
+}
+
+func testAppSourcePaths(t *testing.T, when spec.G, it spec.S) {
+	when("#appSourcePaths", func() {
+		when("AdditionalAppPaths is set", func() {
+			it("returns AppPath followed by the additional paths, in order", func() {
+				p := &Phase{appPath: "/workspace/app", additionalAppPaths: []string{"/workspace/assets", "/workspace/more"}}
+
+				h.AssertEq(t, p.appSourcePaths(), []string{"/workspace/app", "/workspace/assets", "/workspace/more"})
+			})
+		})
+
+		when("AdditionalAppPaths is unset", func() {
+			it("returns just AppPath", func() {
+				p := &Phase{appPath: "/workspace/app"}
+
+				h.AssertEq(t, p.appSourcePaths(), []string{"/workspace/app"})
+			})
+		})
+	})
+
+	when("#createAppReader", func() {
+		var first, second string
+
+		it.Before(func() {
+			var err error
+			first, err = ioutil.TempDir("", "pack-app-source-first")
+			h.AssertNil(t, err)
+
+			second, err = ioutil.TempDir("", "pack-app-source-second")
+			h.AssertNil(t, err)
+
+			h.AssertNil(t, ioutil.WriteFile(filepath.Join(first, "shared.txt"), []byte("from first"), 0644))
+			h.AssertNil(t, ioutil.WriteFile(filepath.Join(first, "only-first.txt"), []byte("only first"), 0644))
+			h.AssertNil(t, ioutil.WriteFile(filepath.Join(second, "shared.txt"), []byte("from second"), 0644))
+		})
+
+		it.After(func() {
+			h.AssertNil(t, os.RemoveAll(first))
+			h.AssertNil(t, os.RemoveAll(second))
+		})
+
+		it("merges later sources over earlier ones on collision", func() {
+			p := &Phase{
+				appPath:            first,
+				additionalAppPaths: []string{second},
+				appDir:             "/workspace",
+			}
+
+			contents := map[string]string{}
+			for _, appPath := range p.appSourcePaths() {
+				reader, err := p.createAppReader(appPath)
+				h.AssertNil(t, err)
+
+				tr := tar.NewReader(reader)
+				for {
+					hdr, err := tr.Next()
+					if err == io.EOF {
+						break
+					}
+					h.AssertNil(t, err)
+
+					if hdr.Typeflag == tar.TypeDir {
+						continue
+					}
+
+					data, err := ioutil.ReadAll(tr)
+					h.AssertNil(t, err)
+					contents[filepath.Base(hdr.Name)] = string(data)
+				}
+				reader.Close()
+			}
+
+			h.AssertEq(t, contents["shared.txt"], "from second")
+			h.AssertEq(t, contents["only-first.txt"], "only first")
+		})
+	})
+}
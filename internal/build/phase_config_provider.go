@@ -21,18 +21,29 @@ func NewPhaseConfigProvider(name string, lifecycle *Lifecycle, ops ...PhaseConfi
 		name:     name,
 	}
 
-	provider.ctrConf.Cmd = []string{"/cnb/lifecycle/" + name}
+	provider.ctrConf.Cmd = []string{lifecycleDir + "/" + name}
 	provider.ctrConf.Image = lifecycle.builder.Name()
 	provider.ctrConf.Labels = map[string]string{"author": "pack"}
 
 	ops = append(ops,
 		WithLifecycleProxy(lifecycle),
 		WithBinds([]string{
-			fmt.Sprintf("%s:%s", lifecycle.LayersVolume, layersDir),
-			fmt.Sprintf("%s:%s", lifecycle.AppVolume, appDir),
+			fmt.Sprintf("%s:%s", lifecycle.LayersVolume, lifecycle.paths.layersDir()),
+			fmt.Sprintf("%s:%s", lifecycle.AppVolume, lifecycle.paths.appDir()),
 		}...),
+		WithResources(lifecycle.resources),
 	)
 
+	if !lifecycle.isWindows() {
+		// GroupAdd grants supplementary GIDs, a Linux process-credential concept Windows
+		// containers have no equivalent for.
+		ops = append(ops, WithGroupAdd(lifecycle.groupAdd))
+	}
+
+	if lifecycle.lifecycleVolume != "" {
+		ops = append(ops, WithBinds(fmt.Sprintf("%s:%s", lifecycle.lifecycleVolume, lifecycleDir)))
+	}
+
 	for _, op := range ops {
 		op(provider)
 	}
@@ -64,13 +75,25 @@ func WithBinds(binds ...string) PhaseConfigProviderOperation {
 	}
 }
 
-func WithDaemonAccess() PhaseConfigProviderOperation {
+// WithDaemonAccess binds the host's Docker socket into the phase container, so a phase running
+// against the daemon (rather than a registry) can reach it directly.
+func WithDaemonAccess(lifecycle *Lifecycle) PhaseConfigProviderOperation {
 	return func(provider *PhaseConfigProvider) {
+		if lifecycle.isWindows() {
+			provider.hostConf.Binds = append(provider.hostConf.Binds, `//./pipe/docker_engine://./pipe/docker_engine`)
+			return
+		}
 		provider.ctrConf.User = "root"
 		provider.hostConf.Binds = append(provider.hostConf.Binds, "/var/run/docker.sock:/var/run/docker.sock")
 	}
 }
 
+func WithGroupAdd(groupAdd []string) PhaseConfigProviderOperation {
+	return func(provider *PhaseConfigProvider) {
+		provider.hostConf.GroupAdd = append(provider.hostConf.GroupAdd, groupAdd...)
+	}
+}
+
 func WithLifecycleProxy(lifecycle *Lifecycle) PhaseConfigProviderOperation {
 	return func(provider *PhaseConfigProvider) {
 		if lifecycle.httpProxy != "" {
@@ -103,8 +126,28 @@ func WithRegistryAccess(authConfig string) PhaseConfigProviderOperation {
 	}
 }
 
-func WithRoot() PhaseConfigProviderOperation {
+// WithRoot runs the phase container's process as root, needed to read the Docker socket
+// WithDaemonAccess binds in, or to write into directories the builder's declared UID/GID don't
+// own. It has no effect on Windows, which has no equivalent to the Linux root user.
+func WithRoot(lifecycle *Lifecycle) PhaseConfigProviderOperation {
 	return func(provider *PhaseConfigProvider) {
+		if lifecycle.isWindows() {
+			return
+		}
 		provider.ctrConf.User = "root"
 	}
 }
+
+func WithResources(resources Resources) PhaseConfigProviderOperation {
+	return func(provider *PhaseConfigProvider) {
+		if resources.Memory != 0 {
+			provider.hostConf.Memory = resources.Memory
+		}
+		if resources.CPUPeriod != 0 {
+			provider.hostConf.CPUPeriod = resources.CPUPeriod
+		}
+		if resources.CPUQuota != 0 {
+			provider.hostConf.CPUQuota = resources.CPUQuota
+		}
+	}
+}
@@ -92,12 +92,25 @@ func testPhaseConfigProvider(t *testing.T, when spec.G, it spec.S) {
 				phaseConfigProvider := build.NewPhaseConfigProvider(
 					"some-name",
 					lifecycle,
-					build.WithDaemonAccess(),
+					build.WithDaemonAccess(lifecycle),
 				)
 
 				h.AssertEq(t, phaseConfigProvider.ContainerConfig().User, "root")
 				h.AssertSliceContains(t, phaseConfigProvider.HostConfig().Binds, "/var/run/docker.sock:/var/run/docker.sock")
 			})
+
+			it("binds the named pipe instead of a socket for a Windows lifecycle", func() {
+				lifecycle := fakeLifecycleWithOS(t, "windows")
+
+				phaseConfigProvider := build.NewPhaseConfigProvider(
+					"some-name",
+					lifecycle,
+					build.WithDaemonAccess(lifecycle),
+				)
+
+				h.AssertEq(t, phaseConfigProvider.ContainerConfig().User, "")
+				h.AssertSliceContains(t, phaseConfigProvider.HostConfig().Binds, `//./pipe/docker_engine://./pipe/docker_engine`)
+			})
 		})
 
 		when("called with WithNetwork", func() {
@@ -117,6 +130,60 @@ func testPhaseConfigProvider(t *testing.T, when spec.G, it spec.S) {
 					container.NetworkMode(expectedNetworkMode),
 				)
 			})
+
+			it("passes through 'none' to sandbox the container from the network", func() {
+				lifecycle := fakeLifecycle(t, false)
+
+				phaseConfigProvider := build.NewPhaseConfigProvider(
+					"some-name",
+					lifecycle,
+					build.WithNetwork("none"),
+				)
+
+				h.AssertEq(
+					t,
+					phaseConfigProvider.HostConfig().NetworkMode,
+					container.NetworkMode("none"),
+				)
+			})
+		})
+
+		when("called with WithGroupAdd", func() {
+			it("sets supplementary GIDs on the config", func() {
+				lifecycle := fakeLifecycle(t, false)
+				expectedGroupAdd := []string{"999"}
+
+				phaseConfigProvider := build.NewPhaseConfigProvider(
+					"some-name",
+					lifecycle,
+					build.WithGroupAdd(expectedGroupAdd),
+				)
+
+				h.AssertSliceContains(t, phaseConfigProvider.HostConfig().GroupAdd, expectedGroupAdd...)
+			})
+		})
+
+		when("GroupAdd is unset on the lifecycle", func() {
+			it("leaves GroupAdd empty by default", func() {
+				lifecycle := fakeLifecycle(t, false)
+
+				phaseConfigProvider := build.NewPhaseConfigProvider("some-name", lifecycle)
+
+				h.AssertEq(t, len(phaseConfigProvider.HostConfig().GroupAdd), 0)
+			})
+		})
+
+		when("the lifecycle targets Windows", func() {
+			it("ignores GroupAdd, which Windows containers have no equivalent for", func() {
+				lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+					opts.TargetOS = "windows"
+					opts.GroupAdd = []string{"999"}
+				})
+
+				phaseConfigProvider := build.NewPhaseConfigProvider("some-name", lifecycle)
+
+				h.AssertEq(t, len(phaseConfigProvider.HostConfig().GroupAdd), 0)
+			})
 		})
 
 		when("called with WithRegistryAccess", func() {
@@ -146,11 +213,67 @@ func testPhaseConfigProvider(t *testing.T, when spec.G, it spec.S) {
 				phaseConfigProvider := build.NewPhaseConfigProvider(
 					"some-name",
 					lifecycle,
-					build.WithRoot(),
+					build.WithRoot(lifecycle),
 				)
 
 				h.AssertEq(t, phaseConfigProvider.ContainerConfig().User, "root")
 			})
+
+			it("has no effect for a Windows lifecycle", func() {
+				lifecycle := fakeLifecycleWithOS(t, "windows")
+
+				phaseConfigProvider := build.NewPhaseConfigProvider(
+					"some-name",
+					lifecycle,
+					build.WithRoot(lifecycle),
+				)
+
+				h.AssertEq(t, phaseConfigProvider.ContainerConfig().User, "")
+			})
+		})
+
+		when("called with WithResources", func() {
+			it("sets CPU and memory limits on the config", func() {
+				lifecycle := fakeLifecycle(t, false)
+				expectedResources := build.Resources{CPUPeriod: 100000, CPUQuota: 50000, Memory: 1 << 20}
+
+				phaseConfigProvider := build.NewPhaseConfigProvider(
+					"some-name",
+					lifecycle,
+					build.WithResources(expectedResources),
+				)
+
+				h.AssertEq(t, phaseConfigProvider.HostConfig().CPUPeriod, expectedResources.CPUPeriod)
+				h.AssertEq(t, phaseConfigProvider.HostConfig().CPUQuota, expectedResources.CPUQuota)
+				h.AssertEq(t, phaseConfigProvider.HostConfig().Memory, expectedResources.Memory)
+			})
+		})
+
+		when("LifecycleOptions.Resources is set", func() {
+			it("reaches the container config without an explicit WithResources call", func() {
+				expectedResources := build.Resources{CPUPeriod: 100000, CPUQuota: 50000, Memory: 1 << 20}
+				lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+					opts.Resources = expectedResources
+				})
+
+				phaseConfigProvider := build.NewPhaseConfigProvider("some-name", lifecycle)
+
+				h.AssertEq(t, phaseConfigProvider.HostConfig().CPUPeriod, expectedResources.CPUPeriod)
+				h.AssertEq(t, phaseConfigProvider.HostConfig().CPUQuota, expectedResources.CPUQuota)
+				h.AssertEq(t, phaseConfigProvider.HostConfig().Memory, expectedResources.Memory)
+			})
+		})
+
+		when("LifecycleOptions.Resources is unset", func() {
+			it("leaves the container unconstrained by default", func() {
+				lifecycle := fakeLifecycle(t, false)
+
+				phaseConfigProvider := build.NewPhaseConfigProvider("some-name", lifecycle)
+
+				h.AssertEq(t, phaseConfigProvider.HostConfig().CPUPeriod, int64(0))
+				h.AssertEq(t, phaseConfigProvider.HostConfig().CPUQuota, int64(0))
+				h.AssertEq(t, phaseConfigProvider.HostConfig().Memory, int64(0))
+			})
 		})
 	})
 }
@@ -0,0 +1,55 @@
+package build
+
+import (
+	"archive/tar"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/archive"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestParseReportDigest(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "parseReportDigest", testParseReportDigest, spec.Report(report.Terminal{}))
+}
+
+func testParseReportDigest(t *testing.T, when spec.G, it spec.S) {
+	when("report.toml has a digest", func() {
+		it("returns it", func() {
+			reportToml := `[image]
+  digest = "sha256:deadbeef"
+  tags = ["latest"]
+`
+			reader := archive.GenerateTar(func(tw *tar.Writer) error {
+				return archive.AddFileToTar(tw, "report.toml", reportToml)
+			})
+			defer reader.Close()
+
+			h.AssertEq(t, parseReportDigest(reader), "sha256:deadbeef")
+		})
+	})
+
+	when("report.toml has no digest", func() {
+		it("returns an empty string", func() {
+			reader := archive.GenerateTar(func(tw *tar.Writer) error {
+				return archive.AddFileToTar(tw, "report.toml", "[image]\n")
+			})
+			defer reader.Close()
+
+			h.AssertEq(t, parseReportDigest(reader), "")
+		})
+	})
+
+	when("the archive is malformed", func() {
+		it("returns an empty string", func() {
+			h.AssertEq(t, parseReportDigest(strings.NewReader("not a tar")), "")
+		})
+	})
+}
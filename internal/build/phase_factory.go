@@ -10,15 +10,23 @@ func NewDefaultPhaseFactory(lifecycle *Lifecycle) *DefaultPhaseFactory {
 
 func (m *DefaultPhaseFactory) New(provider *PhaseConfigProvider) RunnerCleaner {
 	return &Phase{
-		ctrConf:    provider.ContainerConfig(),
-		hostConf:   provider.HostConfig(),
-		name:       provider.Name(),
-		docker:     m.lifecycle.docker,
-		logger:     m.lifecycle.logger,
-		uid:        m.lifecycle.builder.UID(),
-		gid:        m.lifecycle.builder.GID(),
-		appPath:    m.lifecycle.appPath,
-		appOnce:    m.lifecycle.appOnce,
-		fileFilter: m.lifecycle.fileFilter,
+		ctrConf:            provider.ContainerConfig(),
+		hostConf:           provider.HostConfig(),
+		name:               provider.Name(),
+		docker:             m.lifecycle.docker,
+		logger:             m.lifecycle.logger,
+		uid:                m.lifecycle.builder.UID(),
+		gid:                m.lifecycle.builder.GID(),
+		appPath:            m.lifecycle.appPath,
+		additionalAppPaths: m.lifecycle.additionalAppPaths,
+		appOnce:            m.lifecycle.appOnce,
+		appDir:             m.lifecycle.paths.appDir(),
+		fileFilter:         m.lifecycle.fileFilter,
+		platformDir:        m.lifecycle.paths.platformDir(),
+		platformFiles:      m.lifecycle.platformFiles,
+		platformOnce:       m.lifecycle.platformOnce,
+		layersDir:          m.lifecycle.paths.layersDir(),
+		isExporter:         provider.Name() == "exporter" || provider.Name() == "creator",
+		isBuilder:          provider.Name() == "builder",
 	}
 }
@@ -231,7 +231,7 @@ func testPhase(t *testing.T, when spec.G, it spec.S) {
 
 			when("#WithDaemonAccess", func() {
 				it("allows daemon access inside the container", func() {
-					configProvider := build.NewPhaseConfigProvider(phaseName, lifecycle, build.WithArgs("daemon"), build.WithDaemonAccess())
+					configProvider := build.NewPhaseConfigProvider(phaseName, lifecycle, build.WithArgs("daemon"), build.WithDaemonAccess(lifecycle))
 					phase := phaseFactory.New(configProvider)
 					assertRunSucceeds(t, phase, &outBuf, &errBuf)
 					h.AssertContains(t, outBuf.String(), "[phase] daemon test")
@@ -240,7 +240,7 @@ func testPhase(t *testing.T, when spec.G, it spec.S) {
 
 			when("#WithRoot", func() {
 				it("sets the containers user to root", func() {
-					configProvider := build.NewPhaseConfigProvider(phaseName, lifecycle, build.WithArgs("user"), build.WithRoot())
+					configProvider := build.NewPhaseConfigProvider(phaseName, lifecycle, build.WithArgs("user"), build.WithRoot(lifecycle))
 					phase := phaseFactory.New(configProvider)
 					assertRunSucceeds(t, phase, &outBuf, &errBuf)
 					h.AssertContains(t, outBuf.String(), "[phase] current user is root")
@@ -374,12 +374,14 @@ func CreateFakeLifecycle(docker client.CommonAPIClient, logger logging.Logger, a
 		return nil, err
 	}
 
-	subject.Setup(build.LifecycleOptions{
+	if err := subject.Setup(build.LifecycleOptions{
 		AppPath:    appDir,
 		Builder:    bldr,
 		HTTPProxy:  "some-http-proxy",
 		HTTPSProxy: "some-https-proxy",
 		NoProxy:    "some-no-proxy",
-	})
+	}); err != nil {
+		return nil, err
+	}
 	return subject, nil
 }
@@ -3,26 +3,46 @@ package build
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/Masterminds/semver"
 	"github.com/buildpacks/lifecycle/auth"
-	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/internal/api"
+	"github.com/buildpacks/pack/internal/container"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
 )
 
 const (
-	layersDir                 = "/layers"
-	appDir                    = "/workspace"
-	cacheDir                  = "/cache"
-	launchCacheDir            = "/launch-cache"
-	platformDir               = "/platform"
 	defaultProcessPlatformAPI = "0.3"
+	// creatorMinimumVersion is the earliest lifecycle version that ships a single `creator`
+	// binary able to run detect/analyze/restore/build/export in one container.
+	creatorMinimumVersion = "0.7.4"
+	// orderOverridePlatformAPI is the minimum Platform API that supports overriding the
+	// builder's order.toml via the `-order` flag.
+	orderOverridePlatformAPI = "0.3"
+	// cacheImagePlatformAPI is the minimum Platform API that supports the `-cache-image` flag,
+	// which points ANALYZING, RESTORING, and EXPORTING (or CREATING) at a registry-hosted build
+	// cache instead of a local volume or bind-mounted directory.
+	cacheImagePlatformAPI = "0.3"
 )
 
 type RunnerCleaner interface {
 	Run(ctx context.Context) error
 	Cleanup() error
+
+	// Digest returns the image digest the exporter reported in its report.toml after a
+	// successful Run, or "" if the phase isn't an exporter or didn't publish to a registry.
+	Digest() string
+
+	// ProcessTypes returns the process types declared in the builder's metadata.toml after a
+	// successful Run, or nil if the phase isn't a builder.
+	ProcessTypes() []string
 }
 
 type PhaseFactory interface {
@@ -30,15 +50,29 @@ type PhaseFactory interface {
 }
 
 func (l *Lifecycle) Detect(ctx context.Context, networkMode string, volumes []string, phaseFactory PhaseFactory) error {
+	if l.PlatformVolume != "" {
+		volumes = append(volumes, fmt.Sprintf("%s:%s", l.PlatformVolume, l.paths.platformDir()))
+	}
+
+	args := l.withLogLevel(
+		"-app", l.paths.appDir(),
+		"-platform", l.paths.platformDir(),
+	)
+
+	if l.orderPath != "" {
+		supportsOrderOverride := api.MustParse(l.platformAPIVersion).SupportsVersion(api.MustParse(orderOverridePlatformAPI))
+		if supportsOrderOverride {
+			args = append([]string{"-order", l.paths.orderPath()}, args...)
+			volumes = append(volumes, fmt.Sprintf("%s:%s", l.orderPath, l.paths.orderPath()))
+		} else {
+			l.logger.Warn("You specified an order.toml override but that is not supported by this version of the platform API")
+		}
+	}
+
 	configProvider := NewPhaseConfigProvider(
 		"detector",
 		l,
-		WithArgs(
-			l.withLogLevel(
-				"-app", appDir,
-				"-platform", platformDir,
-			)...,
-		),
+		WithArgs(args...),
 		WithNetwork(networkMode),
 		WithBinds(volumes...),
 	)
@@ -49,25 +83,67 @@ func (l *Lifecycle) Detect(ctx context.Context, networkMode string, volumes []st
 }
 
 func (l *Lifecycle) Restore(ctx context.Context, cacheName string, phaseFactory PhaseFactory) error {
-	configProvider := NewPhaseConfigProvider(
-		"restorer",
-		l,
-		WithRoot(), // remove after platform API 0.2 is no longer supported
-		WithArgs(
-			l.withLogLevel(
-				"-cache-dir", cacheDir,
-				"-layers", layersDir,
-			)...,
-		),
-		WithBinds(fmt.Sprintf("%s:%s", cacheName, cacheDir)),
-	)
+	if l.cacheImage == "" && l.cacheIsEmpty(ctx, cacheName) {
+		l.logger.Info("Skipping 'restore' (cache is empty)")
+		return nil
+	}
+
+	args := []string{"-layers", l.paths.layersDir()}
+	opts := []PhaseConfigProviderOperation{
+		WithRoot(l), // remove after platform API 0.2 is no longer supported
+	}
+
+	if l.cacheImage != "" {
+		authConfig, err := auth.BuildEnvVar(l.keychain, l.cacheImage)
+		if err != nil {
+			return err
+		}
+		args = append([]string{"-cache-image", l.cacheImage}, args...)
+		opts = append(opts, WithRegistryAccess(authConfig))
+	} else {
+		args = append([]string{"-cache-dir", l.paths.cacheDir()}, args...)
+		opts = append(opts, WithBinds(fmt.Sprintf("%s:%s", cacheName, l.paths.cacheDir())))
+	}
+	opts = append(opts, WithArgs(l.withLogLevel(args...)...))
+
+	configProvider := NewPhaseConfigProvider("restorer", l, opts...)
 
 	restore := phaseFactory.New(configProvider)
 	defer restore.Cleanup()
 	return restore.Run(ctx)
 }
 
+// cacheIsEmpty reports whether the named cache volume has no layers written into it yet, so
+// Restore can skip creating a container that would otherwise just run RESTORE with nothing to
+// extract. A volume that doesn't exist yet counts as empty. Anything pack can't be sure about --
+// an inspect error, or a volume whose usage data the driver doesn't report -- counts as not
+// empty, since RESTORE running unnecessarily is harmless but skipping it while the cache does
+// have layers would not be.
+func (l *Lifecycle) cacheIsEmpty(ctx context.Context, cacheName string) bool {
+	volume, err := l.docker.VolumeInspect(ctx, cacheName)
+	if err != nil {
+		return client.IsErrNotFound(err)
+	}
+
+	if volume.UsageData == nil {
+		return false
+	}
+
+	return volume.UsageData.Size == 0
+}
+
 func (l *Lifecycle) Analyze(ctx context.Context, repoName, cacheName string, publish, clearCache bool, phaseFactory PhaseFactory) error {
+	if l.skipMissingPreviousImage && !clearCache {
+		exists, err := previousImageExists(l.docker, l.keychain, repoName, publish)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			l.logger.Infof("Previous image %s does not exist; skipping layer restoration", style.Symbol(repoName))
+			clearCache = true
+		}
+	}
+
 	analyze, err := l.newAnalyze(repoName, cacheName, publish, clearCache, phaseFactory)
 	if err != nil {
 		return err
@@ -78,17 +154,28 @@ func (l *Lifecycle) Analyze(ctx context.Context, repoName, cacheName string, pub
 
 func (l *Lifecycle) newAnalyze(repoName, cacheName string, publish, clearCache bool, phaseFactory PhaseFactory) (RunnerCleaner, error) {
 	args := []string{
-		"-layers", layersDir,
+		"-layers", l.paths.layersDir(),
 		repoName,
 	}
-	if clearCache {
+
+	var cacheImages []string
+	switch {
+	case clearCache:
 		args = prependArg("-skip-layers", args)
-	} else {
-		args = append([]string{"-cache-dir", cacheDir}, args...)
+	case l.cacheImage != "":
+		args = append([]string{"-cache-image", l.cacheImage}, args...)
+		cacheImages = []string{l.cacheImage}
+	default:
+		args = append([]string{"-cache-dir", l.paths.cacheDir()}, args...)
+	}
+
+	var cacheBinds []string
+	if l.cacheImage == "" {
+		cacheBinds = []string{fmt.Sprintf("%s:%s", cacheName, l.paths.cacheDir())}
 	}
 
 	if publish {
-		authConfig, err := auth.BuildEnvVar(authn.DefaultKeychain, repoName)
+		authConfig, err := auth.BuildEnvVar(l.keychain, append([]string{repoName}, cacheImages...)...)
 		if err != nil {
 			return nil, err
 		}
@@ -97,18 +184,16 @@ func (l *Lifecycle) newAnalyze(repoName, cacheName string, publish, clearCache b
 			"analyzer",
 			l,
 			WithRegistryAccess(authConfig),
-			WithRoot(),
+			WithRoot(l),
 			WithArgs(args...),
-			WithBinds(fmt.Sprintf("%s:%s", cacheName, cacheDir)),
+			WithBinds(cacheBinds...),
 		)
 
 		return phaseFactory.New(configProvider), nil
 	}
 
-	configProvider := NewPhaseConfigProvider(
-		"analyzer",
-		l,
-		WithDaemonAccess(),
+	opts := []PhaseConfigProviderOperation{
+		WithDaemonAccess(l),
 		WithArgs(
 			l.withLogLevel(
 				prependArg(
@@ -117,8 +202,17 @@ func (l *Lifecycle) newAnalyze(repoName, cacheName string, publish, clearCache b
 				)...,
 			)...,
 		),
-		WithBinds(fmt.Sprintf("%s:%s", cacheName, cacheDir)),
-	)
+		WithBinds(cacheBinds...),
+	}
+	if len(cacheImages) > 0 {
+		authConfig, err := auth.BuildEnvVar(l.keychain, cacheImages...)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithRegistryAccess(authConfig))
+	}
+
+	configProvider := NewPhaseConfigProvider("analyzer", l, opts...)
 
 	return phaseFactory.New(configProvider), nil
 }
@@ -127,14 +221,49 @@ func prependArg(arg string, args []string) []string {
 	return append([]string{arg}, args...)
 }
 
+// runPreBuildHook runs hook.Command inside hook.Image, with the layers and app volumes mounted at
+// their usual paths, so it can inspect what RESTORING populated before BUILDING starts.
+func (l *Lifecycle) runPreBuildHook(ctx context.Context, hook *PreBuildHook) error {
+	ctrConf := &dcontainer.Config{
+		Image: hook.Image,
+		Cmd:   hook.Command,
+	}
+	hostConf := &dcontainer.HostConfig{
+		Binds: []string{
+			fmt.Sprintf("%s:%s", l.LayersVolume, l.paths.layersDir()),
+			fmt.Sprintf("%s:%s", l.AppVolume, l.paths.appDir()),
+		},
+	}
+
+	ctr, err := l.docker.ContainerCreate(ctx, ctrConf, hostConf, nil, "")
+	if err != nil {
+		return errors.Wrap(err, "failed to create pre-build hook container")
+	}
+	defer l.docker.ContainerRemove(context.Background(), ctr.ID, types.ContainerRemoveOptions{Force: true})
+
+	if err := container.Run(
+		ctx,
+		l.docker,
+		ctr.ID,
+		logging.NewPrefixWriter(logging.GetWriterForLevel(l.logger, logging.InfoLevel), "pre-build-hook"),
+		logging.NewPrefixWriter(logging.GetWriterForLevel(l.logger, logging.ErrorLevel), "pre-build-hook"),
+	); err != nil {
+		if exitErr, ok := err.(*container.ExitError); ok {
+			return &FailedPhaseError{Name: "pre-build-hook", ExitCode: exitErr.ExitCode}
+		}
+		return errors.Wrap(err, "running pre-build hook")
+	}
+	return nil
+}
+
 func (l *Lifecycle) Build(ctx context.Context, networkMode string, volumes []string, phaseFactory PhaseFactory) error {
 	configProvider := NewPhaseConfigProvider(
 		"builder",
 		l,
 		WithArgs(
-			"-layers", layersDir,
-			"-app", appDir,
-			"-platform", platformDir,
+			"-layers", l.paths.layersDir(),
+			"-app", l.paths.appDir(),
+			"-platform", l.paths.platformDir(),
 		),
 		WithNetwork(networkMode),
 		WithBinds(volumes...),
@@ -142,31 +271,109 @@ func (l *Lifecycle) Build(ctx context.Context, networkMode string, volumes []str
 
 	build := phaseFactory.New(configProvider)
 	defer build.Cleanup()
-	return build.Run(ctx)
+	if err := build.Run(ctx); err != nil {
+		return err
+	}
+
+	if l.DefaultProcessType == "" {
+		return nil
+	}
+	processTypes := build.ProcessTypes()
+	for _, processType := range processTypes {
+		if processType == l.DefaultProcessType {
+			return nil
+		}
+	}
+	return errors.Errorf(
+		"default process type %s not found in build metadata; available process types: %s",
+		style.Symbol(l.DefaultProcessType),
+		strings.Join(processTypes, ", "),
+	)
 }
 
-func (l *Lifecycle) Export(ctx context.Context, repoName string, runImage string, publish bool, launchCacheName, cacheName string, phaseFactory PhaseFactory) error {
-	export, err := l.newExport(repoName, runImage, publish, launchCacheName, cacheName, phaseFactory)
+func (l *Lifecycle) Export(ctx context.Context, repoName string, additionalTags []string, runImage string, publish bool, launchCacheName, cacheName string, phaseFactory PhaseFactory) error {
+	export, err := l.newExport(repoName, additionalTags, runImage, publish, launchCacheName, cacheName, phaseFactory)
 	if err != nil {
 		return err
 	}
 	defer export.Cleanup()
-	return export.Run(ctx)
+	if err := export.Run(ctx); err != nil {
+		return err
+	}
+	l.digest = export.Digest()
+	return nil
 }
 
-func (l *Lifecycle) newExport(repoName, runImage string, publish bool, launchCacheName, cacheName string, phaseFactory PhaseFactory) (RunnerCleaner, error) {
+// warnUnsupportedProcessOverrides logs a warning for each entry in l.processOverrides, since this
+// lifecycle has no flag or file EXPORTING (or CREATING) reads to override a process's working
+// directory or args -- those are fixed by the buildpack that declared the process at BUILDING
+// time. Call it before running either phase so callers aren't left thinking an override they set
+// was silently honored.
+func (l *Lifecycle) warnUnsupportedProcessOverrides() {
+	for processType := range l.processOverrides {
+		l.logger.Warn(fmt.Sprintf(
+			"You specified an override for process type %s but that is not supported by this version of the lifecycle",
+			style.Symbol(processType),
+		))
+	}
+}
+
+func (l *Lifecycle) newExport(repoName string, additionalTags []string, runImage string, publish bool, launchCacheName, cacheName string, phaseFactory PhaseFactory) (RunnerCleaner, error) {
+	l.warnUnsupportedProcessOverrides()
+
 	args := l.exportImageArgs(runImage)
+	args = append(args, labelArgs(l.labels)...)
+	args = append(args, annotationArgs(l.annotations)...)
+
+	var binds, cacheImages []string
+	if l.cacheImage != "" {
+		args = append(args, "-cache-image", l.cacheImage)
+		cacheImages = []string{l.cacheImage}
+	} else {
+		args = append(args, "-cache-dir", l.paths.cacheDir())
+		binds = append(binds, fmt.Sprintf("%s:%s", cacheName, l.paths.cacheDir()))
+	}
 	args = append(args, []string{
-		"-cache-dir", cacheDir,
-		"-layers", layersDir,
-		"-app", appDir,
-		repoName,
+		"-layers", l.paths.layersDir(),
+		"-app", l.paths.appDir(),
 	}...)
+	args = append(args, repoName)
+	args = append(args, additionalTags...)
+
+	if l.ociLayoutDir != "" {
+		args = append([]string{"-layout", "-layout-dir", l.paths.ociLayoutDir()}, args...)
+		binds = append(binds, fmt.Sprintf("%s:%s", l.ociLayoutDir, l.paths.ociLayoutDir()))
+
+		if l.DefaultProcessType != "" {
+			supportsDefaultProcess := api.MustParse(l.platformAPIVersion).SupportsVersion(api.MustParse(defaultProcessPlatformAPI))
+			if supportsDefaultProcess {
+				args = append([]string{"-process-type", l.DefaultProcessType}, args...)
+			} else {
+				l.logger.Warn("You specified a default process type but that is not supported by this version of the lifecycle")
+			}
+		}
 
-	binds := []string{fmt.Sprintf("%s:%s", cacheName, cacheDir)}
+		opts := []PhaseConfigProviderOperation{
+			WithArgs(
+				l.withLogLevel(args...)...,
+			),
+			WithBinds(binds...),
+		}
+		if len(cacheImages) > 0 {
+			authConfig, err := auth.BuildEnvVar(l.keychain, cacheImages...)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, WithRegistryAccess(authConfig), WithRoot(l))
+		}
+
+		configProvider := NewPhaseConfigProvider("exporter", l, opts...)
+
+		return phaseFactory.New(configProvider), nil
+	}
 
 	if publish {
-		authConfig, err := auth.BuildEnvVar(authn.DefaultKeychain, repoName, runImage)
+		authConfig, err := auth.BuildEnvVar(l.keychain, append(append([]string{repoName, runImage}, additionalTags...), cacheImages...)...)
 		if err != nil {
 			return nil, err
 		}
@@ -178,15 +385,15 @@ func (l *Lifecycle) newExport(repoName, runImage string, publish bool, launchCac
 			WithArgs(
 				l.withLogLevel(args...)...,
 			),
-			WithRoot(),
+			WithRoot(l),
 			WithBinds(binds...),
 		)
 
 		return phaseFactory.New(configProvider), nil
 	}
 
-	args = append([]string{"-daemon", "-launch-cache", launchCacheDir}, args...)
-	binds = append(binds, fmt.Sprintf("%s:%s", launchCacheName, launchCacheDir))
+	args = append([]string{"-daemon", "-launch-cache", l.paths.launchCacheDir()}, args...)
+	binds = append(binds, fmt.Sprintf("%s:%s", launchCacheName, l.paths.launchCacheDir()))
 
 	if l.DefaultProcessType != "" {
 		supportsDefaultProcess := api.MustParse(l.platformAPIVersion).SupportsVersion(api.MustParse(defaultProcessPlatformAPI))
@@ -197,15 +404,144 @@ func (l *Lifecycle) newExport(repoName, runImage string, publish bool, launchCac
 		}
 	}
 
-	configProvider := NewPhaseConfigProvider(
-		"exporter",
-		l,
-		WithDaemonAccess(),
+	opts := []PhaseConfigProviderOperation{
+		WithDaemonAccess(l),
 		WithArgs(
 			l.withLogLevel(args...)...,
 		),
 		WithBinds(binds...),
-	)
+	}
+	if len(cacheImages) > 0 {
+		authConfig, err := auth.BuildEnvVar(l.keychain, cacheImages...)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithRegistryAccess(authConfig))
+	}
+
+	configProvider := NewPhaseConfigProvider("exporter", l, opts...)
+
+	return phaseFactory.New(configProvider), nil
+}
+
+// supportsCreator reports whether the builder's lifecycle ships a `creator` binary capable of
+// running detect/analyze/restore/build/export in a single container.
+func (l *Lifecycle) supportsCreator() bool {
+	return semver.MustParse(l.version).Compare(semver.MustParse(creatorMinimumVersion)) >= 0
+}
+
+func (l *Lifecycle) Create(ctx context.Context, networkMode string, volumes []string, repoName string, additionalTags []string, runImage string, publish, clearCache bool, launchCacheName, cacheName string, phaseFactory PhaseFactory) error {
+	create, err := l.newCreate(networkMode, volumes, repoName, additionalTags, runImage, publish, clearCache, launchCacheName, cacheName, phaseFactory)
+	if err != nil {
+		return err
+	}
+	defer create.Cleanup()
+	if err := create.Run(ctx); err != nil {
+		return err
+	}
+	l.digest = create.Digest()
+	return nil
+}
+
+func (l *Lifecycle) newCreate(networkMode string, volumes []string, repoName string, additionalTags []string, runImage string, publish, clearCache bool, launchCacheName, cacheName string, phaseFactory PhaseFactory) (RunnerCleaner, error) {
+	l.warnUnsupportedProcessOverrides()
+
+	args := l.exportImageArgs(runImage)
+	args = append(args, labelArgs(l.labels)...)
+	args = append(args, annotationArgs(l.annotations)...)
+	args = append(args, "-app", l.paths.appDir())
+
+	var cacheBind string
+	var cacheImages []string
+	if l.cacheImage != "" {
+		args = append(args, "-cache-image", l.cacheImage)
+		cacheImages = []string{l.cacheImage}
+	} else {
+		args = append(args, "-cache-dir", l.paths.cacheDir())
+		cacheBind = fmt.Sprintf("%s:%s", cacheName, l.paths.cacheDir())
+	}
+
+	args = append(args, "-layers", l.paths.layersDir(), "-platform", l.paths.platformDir())
+	args = append(args, repoName)
+	args = append(args, additionalTags...)
+
+	if clearCache {
+		args = prependArg("-skip-restore", args)
+	}
+
+	if l.DefaultProcessType != "" {
+		supportsDefaultProcess := api.MustParse(l.platformAPIVersion).SupportsVersion(api.MustParse(defaultProcessPlatformAPI))
+		if supportsDefaultProcess {
+			args = append([]string{"-process-type", l.DefaultProcessType}, args...)
+		} else {
+			l.logger.Warn("You specified a default process type but that is not supported by this version of the lifecycle")
+		}
+	}
+
+	binds := volumes
+	if cacheBind != "" {
+		binds = append([]string{cacheBind}, binds...)
+	}
+
+	if l.ociLayoutDir != "" {
+		args = append([]string{"-layout", "-layout-dir", l.paths.ociLayoutDir()}, args...)
+		binds = append(binds, fmt.Sprintf("%s:%s", l.ociLayoutDir, l.paths.ociLayoutDir()))
+
+		opts := []PhaseConfigProviderOperation{
+			WithArgs(l.withLogLevel(args...)...),
+			WithNetwork(networkMode),
+			WithBinds(binds...),
+		}
+		if len(cacheImages) > 0 {
+			authConfig, err := auth.BuildEnvVar(l.keychain, cacheImages...)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, WithRegistryAccess(authConfig), WithRoot(l))
+		}
+
+		configProvider := NewPhaseConfigProvider("creator", l, opts...)
+
+		return phaseFactory.New(configProvider), nil
+	}
+
+	if publish {
+		authConfig, err := auth.BuildEnvVar(l.keychain, append(append([]string{repoName, runImage}, additionalTags...), cacheImages...)...)
+		if err != nil {
+			return nil, err
+		}
+
+		configProvider := NewPhaseConfigProvider(
+			"creator",
+			l,
+			WithRegistryAccess(authConfig),
+			WithRoot(l),
+			WithArgs(l.withLogLevel(args...)...),
+			WithNetwork(networkMode),
+			WithBinds(binds...),
+		)
+
+		return phaseFactory.New(configProvider), nil
+	}
+
+	args = append([]string{"-daemon", "-launch-cache", l.paths.launchCacheDir()}, args...)
+	binds = append(binds, fmt.Sprintf("%s:%s", launchCacheName, l.paths.launchCacheDir()))
+
+	opts := []PhaseConfigProviderOperation{
+		WithDaemonAccess(l),
+		WithArgs(l.withLogLevel(args...)...),
+		WithNetwork(networkMode),
+		WithBinds(binds...),
+	}
+	if len(cacheImages) > 0 {
+		authConfig, err := auth.BuildEnvVar(l.keychain, cacheImages...)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithRegistryAccess(authConfig))
+	}
+
+	configProvider := NewPhaseConfigProvider("creator", l, opts...)
 
 	return phaseFactory.New(configProvider), nil
 }
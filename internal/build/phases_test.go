@@ -5,6 +5,7 @@ import (
 	ioutil "io/ioutil"
 	"math/rand"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -89,6 +90,17 @@ func testPhases(t *testing.T, when spec.G, it spec.S) {
 			h.AssertEq(t, configProvider.HostConfig().NetworkMode, container.NetworkMode(expectedNetworkMode))
 		})
 
+		it("sandboxes the container from the network when configured with 'none'", func() {
+			lifecycle := fakeLifecycle(t, false)
+			fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+			err := lifecycle.Detect(context.Background(), "none", []string{}, fakePhaseFactory)
+			h.AssertNil(t, err)
+
+			configProvider := fakePhaseFactory.NewCalledWithProvider
+			h.AssertEq(t, configProvider.HostConfig().NetworkMode, container.NetworkMode("none"))
+		})
+
 		it("configures the phase with binds", func() {
 			lifecycle := fakeLifecycle(t, false)
 			fakePhaseFactory := fakes.NewFakePhaseFactory()
@@ -100,6 +112,61 @@ func testPhases(t *testing.T, when spec.G, it spec.S) {
 			configProvider := fakePhaseFactory.NewCalledWithProvider
 			h.AssertSliceContains(t, configProvider.HostConfig().Binds, expectedBind)
 		})
+
+		when("PlatformFiles is set", func() {
+			it("binds a platform volume at the platform directory", func() {
+				lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+					opts.PlatformFiles = map[string][]byte{"project-metadata.toml": []byte("some-contents")}
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Detect(context.Background(), "test", []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider
+				h.AssertSliceContainsMatch(t, configProvider.HostConfig().Binds, "pack-platform-.*:/platform")
+			})
+		})
+
+		when("OrderPath is set", func() {
+			it("binds the override order.toml and passes -order when the platform API supports it", func() {
+				lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+					opts.OrderPath = "/host/order.toml"
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Detect(context.Background(), "test", []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider
+				h.AssertSliceContains(t, configProvider.HostConfig().Binds, "/host/order.toml:/cnb/order.toml")
+				h.AssertIncludeAllExpectedPatterns(t,
+					configProvider.ContainerConfig().Cmd,
+					[]string{"-order", "/cnb/order.toml"},
+				)
+			})
+
+			it("ignores the override on platform APIs that don't support it", func() {
+				platformAPIVersion, err := api.NewVersion("0.2")
+				h.AssertNil(t, err)
+				fakeBuilder, err := fakes.NewFakeBuilder(fakes.WithPlatformVersion(platformAPIVersion))
+				h.AssertNil(t, err)
+				lifecycle := fakeLifecycle(t, false, fakes.WithBuilder(fakeBuilder), func(opts *build.LifecycleOptions) {
+					opts.OrderPath = "/host/order.toml"
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err = lifecycle.Detect(context.Background(), "test", []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider
+				for _, bind := range configProvider.HostConfig().Binds {
+					if bind == "/host/order.toml:/cnb/order.toml" {
+						t.Fatalf("expected order.toml override not to be bound, got binds: %s", configProvider.HostConfig().Binds)
+					}
+				}
+			})
+		})
 	})
 
 	when("#Analyze", func() {
@@ -247,6 +314,30 @@ func testPhases(t *testing.T, when spec.G, it spec.S) {
 				configProvider := fakePhaseFactory.NewCalledWithProvider
 				h.AssertSliceContains(t, configProvider.HostConfig().Binds, expectedBind)
 			})
+
+			when("CacheImage is set", func() {
+				it("uses -cache-image instead of a cache volume, with registry access for it", func() {
+					lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+						opts.CacheImage = "some-registry.io/some/cache"
+					})
+					fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+					err := lifecycle.Analyze(context.Background(), "test", "some-cache", false, false, fakePhaseFactory)
+					h.AssertNil(t, err)
+
+					configProvider := fakePhaseFactory.NewCalledWithProvider
+					h.AssertIncludeAllExpectedPatterns(t,
+						configProvider.ContainerConfig().Cmd,
+						[]string{"-daemon"},
+						[]string{"-cache-image", "some-registry.io/some/cache"},
+					)
+					h.AssertSliceContains(t, configProvider.ContainerConfig().Env, "CNB_REGISTRY_AUTH={}")
+					h.AssertSliceContains(t, configProvider.HostConfig().Binds, "/var/run/docker.sock:/var/run/docker.sock")
+					for _, bind := range configProvider.HostConfig().Binds {
+						h.AssertTrue(t, !strings.Contains(bind, ":/cache"))
+					}
+				})
+			})
 		})
 	})
 
@@ -302,6 +393,28 @@ func testPhases(t *testing.T, when spec.G, it spec.S) {
 			configProvider := fakePhaseFactory.NewCalledWithProvider
 			h.AssertSliceContains(t, configProvider.HostConfig().Binds, expectedBind)
 		})
+
+		when("CacheImage is set", func() {
+			it("uses -cache-image instead of a cache volume", func() {
+				lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+					opts.CacheImage = "some-registry.io/some/cache"
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Restore(context.Background(), "some-cache", fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider
+				h.AssertIncludeAllExpectedPatterns(t,
+					configProvider.ContainerConfig().Cmd,
+					[]string{"-cache-image", "some-registry.io/some/cache"},
+				)
+				h.AssertSliceContains(t, configProvider.ContainerConfig().Env, "CNB_REGISTRY_AUTH={}")
+				for _, bind := range configProvider.HostConfig().Binds {
+					h.AssertTrue(t, !strings.Contains(bind, ":/cache"))
+				}
+			})
+		})
 	})
 
 	when("#Build", func() {
@@ -358,6 +471,30 @@ func testPhases(t *testing.T, when spec.G, it spec.S) {
 			configProvider := fakePhaseFactory.NewCalledWithProvider
 			h.AssertSliceContains(t, configProvider.HostConfig().Binds, expectedBind)
 		})
+
+		when("DefaultProcessType is set", func() {
+			it("succeeds when the process type was declared in the build metadata", func() {
+				lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+					opts.DefaultProcessType = "web"
+				})
+				fakePhase := &fakes.FakePhase{ReturnForProcessTypes: []string{"web", "worker"}}
+				fakePhaseFactory := fakes.NewFakePhaseFactory(fakes.WhichReturnsForNew(fakePhase))
+
+				err := lifecycle.Build(context.Background(), "test", []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+			})
+
+			it("errors with the available process types when the process type wasn't declared", func() {
+				lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+					opts.DefaultProcessType = "missing"
+				})
+				fakePhase := &fakes.FakePhase{ReturnForProcessTypes: []string{"web", "worker"}}
+				fakePhaseFactory := fakes.NewFakePhaseFactory(fakes.WhichReturnsForNew(fakePhase))
+
+				err := lifecycle.Build(context.Background(), "test", []string{}, fakePhaseFactory)
+				h.AssertError(t, err, "default process type 'missing' not found in build metadata; available process types: web, worker")
+			})
+		})
 	})
 
 	when("#Export", func() {
@@ -366,7 +503,7 @@ func testPhases(t *testing.T, when spec.G, it spec.S) {
 			fakePhase := &fakes.FakePhase{}
 			fakePhaseFactory := fakes.NewFakePhaseFactory(fakes.WhichReturnsForNew(fakePhase))
 
-			err := lifecycle.Export(context.Background(), "test", "test", false, "test", "test", fakePhaseFactory)
+			err := lifecycle.Export(context.Background(), "test", nil, "test", false, "test", "test", fakePhaseFactory)
 			h.AssertNil(t, err)
 
 			h.AssertEq(t, fakePhase.CleanupCallCount, 1)
@@ -378,7 +515,7 @@ func testPhases(t *testing.T, when spec.G, it spec.S) {
 			fakePhaseFactory := fakes.NewFakePhaseFactory()
 			expectedRepoName := "some-repo-name"
 
-			err := verboseLifecycle.Export(context.Background(), expectedRepoName, "test", false, "test", "test", fakePhaseFactory)
+			err := verboseLifecycle.Export(context.Background(), expectedRepoName, nil, "test", false, "test", "test", fakePhaseFactory)
 			h.AssertNil(t, err)
 
 			configProvider := fakePhaseFactory.NewCalledWithProvider
@@ -393,13 +530,64 @@ func testPhases(t *testing.T, when spec.G, it spec.S) {
 			)
 		})
 
+		when("ProcessOverrides is set", func() {
+			it("warns instead of erroring, since this lifecycle can't apply the override", func() {
+				lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+					opts.ProcessOverrides = map[string]build.ProcessOverride{
+						"worker": {WorkingDirectory: "/app/worker"},
+					}
+				})
+				fakePhase := &fakes.FakePhase{}
+				fakePhaseFactory := fakes.NewFakePhaseFactory(fakes.WhichReturnsForNew(fakePhase))
+
+				err := lifecycle.Export(context.Background(), "test", nil, "test", false, "test", "test", fakePhaseFactory)
+				h.AssertNil(t, err)
+			})
+		})
+
+		it("passes additional tags as extra image args", func() {
+			lifecycle := fakeLifecycle(t, false)
+			fakePhaseFactory := fakes.NewFakePhaseFactory()
+			expectedRepoName := "some-repo-name"
+			expectedAdditionalTags := []string{"some-repo-name:latest", "some-repo-name:1.2.3"}
+
+			err := lifecycle.Export(context.Background(), expectedRepoName, expectedAdditionalTags, "test", false, "test", "test", fakePhaseFactory)
+			h.AssertNil(t, err)
+
+			configProvider := fakePhaseFactory.NewCalledWithProvider
+			cmd := configProvider.ContainerConfig().Cmd
+			h.AssertEq(t, cmd[len(cmd)-3], expectedRepoName)
+			h.AssertEq(t, cmd[len(cmd)-2], expectedAdditionalTags[0])
+			h.AssertEq(t, cmd[len(cmd)-1], expectedAdditionalTags[1])
+		})
+
+		when("ExportToOCILayout is set", func() {
+			it("writes to the layout directory instead of the daemon or a registry", func() {
+				lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+					opts.ExportToOCILayout = "/some/host/layout-dir"
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+				expectedBind := "/some/host/layout-dir:/layout"
+
+				err := lifecycle.Export(context.Background(), "test", nil, "test", false, "test", "test", fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider
+				h.AssertIncludeAllExpectedPatterns(t,
+					configProvider.ContainerConfig().Cmd,
+					[]string{"-layout", "-layout-dir", "/layout"},
+				)
+				h.AssertSliceContains(t, configProvider.HostConfig().Binds, expectedBind)
+			})
+		})
+
 		when("publish", func() {
 			it("configures the phase with registry access", func() {
 				lifecycle := fakeLifecycle(t, false)
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedRepos := []string{"some-repo-name", "some-run-image"}
 
-				err := lifecycle.Export(context.Background(), expectedRepos[0], expectedRepos[1], true, "test", "test", fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), expectedRepos[0], nil, expectedRepos[1], true, "test", "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				configProvider := fakePhaseFactory.NewCalledWithProvider
@@ -411,7 +599,7 @@ func testPhases(t *testing.T, when spec.G, it spec.S) {
 				lifecycle := fakeLifecycle(t, false)
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-				err := lifecycle.Export(context.Background(), "test", "test", true, "test", "test", fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), "test", nil, "test", true, "test", "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				configProvider := fakePhaseFactory.NewCalledWithProvider
@@ -423,7 +611,7 @@ func testPhases(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedBind := "some-cache:/cache"
 
-				err := lifecycle.Export(context.Background(), "test", "test", true, "test", "some-cache", fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), "test", nil, "test", true, "test", "some-cache", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				configProvider := fakePhaseFactory.NewCalledWithProvider
@@ -436,7 +624,7 @@ func testPhases(t *testing.T, when spec.G, it spec.S) {
 				lifecycle := fakeLifecycle(t, false)
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-				err := lifecycle.Export(context.Background(), "test", "test", false, "test", "test", fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), "test", nil, "test", false, "test", "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				configProvider := fakePhaseFactory.NewCalledWithProvider
@@ -452,7 +640,7 @@ func testPhases(t *testing.T, when spec.G, it spec.S) {
 				expectedLaunchCacheName := "some-launch-cache"
 				expectedCacheName := "some-cache"
 
-				err := verboseLifecycle.Export(context.Background(), expectedRepoName, expectedRunImage, false, expectedLaunchCacheName, expectedCacheName, fakePhaseFactory)
+				err := verboseLifecycle.Export(context.Background(), expectedRepoName, nil, expectedRunImage, false, expectedLaunchCacheName, expectedCacheName, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				configProvider := fakePhaseFactory.NewCalledWithProvider
@@ -469,12 +657,72 @@ func testPhases(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedBinds := []string{"some-cache:/cache", "some-launch-cache:/launch-cache"}
 
-				err := lifecycle.Export(context.Background(), "test", "test", false, "some-launch-cache", "some-cache", fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), "test", nil, "test", false, "some-launch-cache", "some-cache", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				configProvider := fakePhaseFactory.NewCalledWithProvider
 				h.AssertSliceContains(t, configProvider.HostConfig().Binds, expectedBinds...)
 			})
+
+			when("CacheImage is set", func() {
+				it("uses -cache-image instead of a cache volume, with registry access for it", func() {
+					lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+						opts.CacheImage = "some-registry.io/some/cache"
+					})
+					fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+					err := lifecycle.Export(context.Background(), "test", nil, "test", false, "some-launch-cache", "some-cache", fakePhaseFactory)
+					h.AssertNil(t, err)
+
+					configProvider := fakePhaseFactory.NewCalledWithProvider
+					h.AssertIncludeAllExpectedPatterns(t,
+						configProvider.ContainerConfig().Cmd,
+						[]string{"-daemon"},
+						[]string{"-cache-image", "some-registry.io/some/cache"},
+					)
+					h.AssertSliceContains(t, configProvider.ContainerConfig().Env, "CNB_REGISTRY_AUTH={}")
+					h.AssertSliceContains(t, configProvider.HostConfig().Binds, "some-launch-cache:/launch-cache")
+					for _, bind := range configProvider.HostConfig().Binds {
+						h.AssertTrue(t, !strings.Contains(bind, ":/cache"))
+					}
+				})
+			})
+
+			when("Labels is set", func() {
+				it("passes -label for each entry", func() {
+					lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+						opts.Labels = map[string]string{"com.example.git-sha": "abcd123"}
+					})
+					fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+					err := lifecycle.Export(context.Background(), "test", nil, "test", false, "some-launch-cache", "some-cache", fakePhaseFactory)
+					h.AssertNil(t, err)
+
+					configProvider := fakePhaseFactory.NewCalledWithProvider
+					h.AssertIncludeAllExpectedPatterns(t,
+						configProvider.ContainerConfig().Cmd,
+						[]string{"-label", "com.example.git-sha=abcd123"},
+					)
+				})
+			})
+
+			when("Annotations is set", func() {
+				it("passes -annotation for each entry", func() {
+					lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+						opts.Annotations = map[string]string{"org.opencontainers.image.source": "https://example.com/some/repo"}
+					})
+					fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+					err := lifecycle.Export(context.Background(), "test", nil, "test", false, "some-launch-cache", "some-cache", fakePhaseFactory)
+					h.AssertNil(t, err)
+
+					configProvider := fakePhaseFactory.NewCalledWithProvider
+					h.AssertIncludeAllExpectedPatterns(t,
+						configProvider.ContainerConfig().Cmd,
+						[]string{"-annotation", "org.opencontainers.image.source=https://example.com/some/repo"},
+					)
+				})
+			})
 		})
 
 		when("platform api 0.2", func() {
@@ -487,7 +735,7 @@ func testPhases(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedRunImage := "some-run-image"
 
-				err = lifecycle.Export(context.Background(), "test", expectedRunImage, false, "test", "test", fakePhaseFactory)
+				err = lifecycle.Export(context.Background(), "test", nil, expectedRunImage, false, "test", "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				configProvider := fakePhaseFactory.NewCalledWithProvider
@@ -509,7 +757,7 @@ func testPhases(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedRunImage := "some-run-image"
 
-				err = lifecycle.Export(context.Background(), "test", expectedRunImage, false, "test", "test", fakePhaseFactory)
+				err = lifecycle.Export(context.Background(), "test", nil, expectedRunImage, false, "test", "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				configProvider := fakePhaseFactory.NewCalledWithProvider
@@ -521,6 +769,182 @@ func testPhases(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 	})
+
+	when("#Create", func() {
+		it("creates a phase and then runs it", func() {
+			lifecycle := fakeLifecycle(t, false)
+			fakePhase := &fakes.FakePhase{}
+			fakePhaseFactory := fakes.NewFakePhaseFactory(fakes.WhichReturnsForNew(fakePhase))
+
+			err := lifecycle.Create(context.Background(), "test", []string{}, "test", nil, "test", false, false, "test", "test", fakePhaseFactory)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, fakePhase.CleanupCallCount, 1)
+			h.AssertEq(t, fakePhase.RunCallCount, 1)
+		})
+
+		it("configures the phase with the expected arguments", func() {
+			verboseLifecycle := fakeLifecycle(t, true)
+			fakePhaseFactory := fakes.NewFakePhaseFactory()
+			expectedRepoName := "some-repo-name"
+
+			err := verboseLifecycle.Create(context.Background(), "test", []string{}, expectedRepoName, nil, "test", false, false, "test", "test", fakePhaseFactory)
+			h.AssertNil(t, err)
+
+			configProvider := fakePhaseFactory.NewCalledWithProvider
+			h.AssertEq(t, configProvider.Name(), "creator")
+			h.AssertIncludeAllExpectedPatterns(t,
+				configProvider.ContainerConfig().Cmd,
+				[]string{"-log-level", "debug"},
+				[]string{"-app", "/workspace"},
+				[]string{"-cache-dir", "/cache"},
+				[]string{"-layers", "/layers"},
+				[]string{"-platform", "/platform"},
+				[]string{expectedRepoName},
+			)
+		})
+
+		it("passes additional tags as extra image args", func() {
+			lifecycle := fakeLifecycle(t, false)
+			fakePhaseFactory := fakes.NewFakePhaseFactory()
+			expectedRepoName := "some-repo-name"
+			expectedAdditionalTags := []string{"some-repo-name:latest", "some-repo-name:1.2.3"}
+
+			err := lifecycle.Create(context.Background(), "test", []string{}, expectedRepoName, expectedAdditionalTags, "test", false, false, "test", "test", fakePhaseFactory)
+			h.AssertNil(t, err)
+
+			configProvider := fakePhaseFactory.NewCalledWithProvider
+			cmd := configProvider.ContainerConfig().Cmd
+			h.AssertEq(t, cmd[len(cmd)-3], expectedRepoName)
+			h.AssertEq(t, cmd[len(cmd)-2], expectedAdditionalTags[0])
+			h.AssertEq(t, cmd[len(cmd)-1], expectedAdditionalTags[1])
+		})
+
+		when("ExportToOCILayout is set", func() {
+			it("writes to the layout directory instead of the daemon or a registry", func() {
+				lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+					opts.ExportToOCILayout = "/some/host/layout-dir"
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+				expectedBind := "/some/host/layout-dir:/layout"
+
+				err := lifecycle.Create(context.Background(), "test", []string{}, "test", nil, "test", false, false, "test", "test", fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider
+				h.AssertIncludeAllExpectedPatterns(t,
+					configProvider.ContainerConfig().Cmd,
+					[]string{"-layout", "-layout-dir", "/layout"},
+				)
+				h.AssertSliceContains(t, configProvider.HostConfig().Binds, expectedBind)
+			})
+		})
+
+		it("skips restore when clearCache is true", func() {
+			lifecycle := fakeLifecycle(t, false)
+			fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+			err := lifecycle.Create(context.Background(), "test", []string{}, "test", nil, "test", false, true, "test", "test", fakePhaseFactory)
+			h.AssertNil(t, err)
+
+			configProvider := fakePhaseFactory.NewCalledWithProvider
+			h.AssertIncludeAllExpectedPatterns(t, configProvider.ContainerConfig().Cmd, []string{"-skip-restore"})
+		})
+
+		when("publish", func() {
+			it("configures the phase with registry access", func() {
+				lifecycle := fakeLifecycle(t, false)
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Create(context.Background(), "test", []string{}, "some-repo-name", nil, "some-run-image", true, false, "test", "test", fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider
+				h.AssertSliceContains(t, configProvider.ContainerConfig().Env, "CNB_REGISTRY_AUTH={}")
+				h.AssertEq(t, configProvider.ContainerConfig().User, "root")
+			})
+		})
+
+		when("publish is false", func() {
+			it("configures the phase with daemon access and the launch cache bind", func() {
+				lifecycle := fakeLifecycle(t, false)
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+				expectedBinds := []string{"some-cache:/cache", "some-launch-cache:/launch-cache"}
+
+				err := lifecycle.Create(context.Background(), "test", []string{}, "test", nil, "test", false, false, "some-launch-cache", "some-cache", fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider
+				h.AssertSliceContains(t, configProvider.HostConfig().Binds, "/var/run/docker.sock:/var/run/docker.sock")
+				h.AssertSliceContains(t, configProvider.HostConfig().Binds, expectedBinds...)
+				h.AssertIncludeAllExpectedPatterns(t,
+					configProvider.ContainerConfig().Cmd,
+					[]string{"-daemon"},
+					[]string{"-launch-cache", "/launch-cache"},
+				)
+			})
+
+			when("CacheImage is set", func() {
+				it("uses -cache-image instead of a cache volume, with registry access for it", func() {
+					lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+						opts.CacheImage = "some-registry.io/some/cache"
+					})
+					fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+					err := lifecycle.Create(context.Background(), "test", []string{}, "test", nil, "test", false, false, "some-launch-cache", "some-cache", fakePhaseFactory)
+					h.AssertNil(t, err)
+
+					configProvider := fakePhaseFactory.NewCalledWithProvider
+					h.AssertIncludeAllExpectedPatterns(t,
+						configProvider.ContainerConfig().Cmd,
+						[]string{"-daemon"},
+						[]string{"-cache-image", "some-registry.io/some/cache"},
+					)
+					h.AssertSliceContains(t, configProvider.ContainerConfig().Env, "CNB_REGISTRY_AUTH={}")
+					h.AssertSliceContains(t, configProvider.HostConfig().Binds, "some-launch-cache:/launch-cache")
+					for _, bind := range configProvider.HostConfig().Binds {
+						h.AssertTrue(t, !strings.Contains(bind, ":/cache"))
+					}
+				})
+			})
+
+			when("Labels is set", func() {
+				it("passes -label for each entry", func() {
+					lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+						opts.Labels = map[string]string{"com.example.git-sha": "abcd123"}
+					})
+					fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+					err := lifecycle.Create(context.Background(), "test", []string{}, "test", nil, "test", false, false, "some-launch-cache", "some-cache", fakePhaseFactory)
+					h.AssertNil(t, err)
+
+					configProvider := fakePhaseFactory.NewCalledWithProvider
+					h.AssertIncludeAllExpectedPatterns(t,
+						configProvider.ContainerConfig().Cmd,
+						[]string{"-label", "com.example.git-sha=abcd123"},
+					)
+				})
+			})
+
+			when("Annotations is set", func() {
+				it("passes -annotation for each entry", func() {
+					lifecycle := fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+						opts.Annotations = map[string]string{"org.opencontainers.image.source": "https://example.com/some/repo"}
+					})
+					fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+					err := lifecycle.Create(context.Background(), "test", []string{}, "test", nil, "test", false, false, "some-launch-cache", "some-cache", fakePhaseFactory)
+					h.AssertNil(t, err)
+
+					configProvider := fakePhaseFactory.NewCalledWithProvider
+					h.AssertIncludeAllExpectedPatterns(t,
+						configProvider.ContainerConfig().Cmd,
+						[]string{"-annotation", "org.opencontainers.image.source=https://example.com/some/repo"},
+					)
+				})
+			})
+		})
+	})
 }
 
 func fakeLifecycle(t *testing.T, logVerbose bool, ops ...func(*build.LifecycleOptions)) *build.Lifecycle {
@@ -528,3 +952,9 @@ func fakeLifecycle(t *testing.T, logVerbose bool, ops ...func(*build.LifecycleOp
 	h.AssertNil(t, err)
 	return lifecycle
 }
+
+func fakeLifecycleWithOS(t *testing.T, os string) *build.Lifecycle {
+	return fakeLifecycle(t, false, func(opts *build.LifecycleOptions) {
+		opts.TargetOS = os
+	})
+}
@@ -0,0 +1,46 @@
+package build
+
+import (
+	"archive/tar"
+	"io/ioutil"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/archive"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuildPlatformFilesTar(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "buildPlatformFilesTar", testBuildPlatformFilesTar, spec.Report(report.Terminal{}))
+}
+
+func testBuildPlatformFilesTar(t *testing.T, when spec.G, it spec.S) {
+	when("#buildPlatformFilesTar", func() {
+		it("writes each file under the platform directory with a normalized mod time", func() {
+			reader := buildPlatformFilesTar("/platform", map[string][]byte{
+				"project-metadata.toml": []byte("some-contents"),
+			})
+			defer reader.Close()
+
+			tr := tar.NewReader(reader)
+			header, err := tr.Next()
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, header.Name, "/platform/project-metadata.toml")
+			h.AssertTrue(t, header.ModTime.Equal(archive.NormalizedDateTime))
+
+			contents, err := ioutil.ReadAll(tr)
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(contents), "some-contents")
+
+			_, err = tr.Next()
+			h.AssertError(t, err, "EOF")
+		})
+	})
+}
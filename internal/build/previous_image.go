@@ -0,0 +1,58 @@
+package build
+
+import (
+	"github.com/buildpacks/imgutil/local"
+	"github.com/docker/docker/client"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// previousImageExists reports whether repoName -- the image ANALYZING will read layer metadata
+// from -- already exists, so Analyze can skip that step cleanly on a first-ever build instead of
+// letting the lifecycle's pull failure surface as a warning. It returns an error for genuine
+// problems reaching the daemon or registry (bad credentials, network failures); those should
+// still fail the build, unlike a repoName that simply hasn't been built yet.
+func previousImageExists(docker client.CommonAPIClient, keychain authn.Keychain, repoName string, publish bool) (bool, error) {
+	if !publish {
+		image, err := local.NewImage(repoName, docker, local.FromBaseImage(repoName))
+		if err != nil {
+			return false, errors.Wrapf(err, "determining whether %s already exists", style.Symbol(repoName))
+		}
+		return image.Found(), nil
+	}
+
+	ref, err := name.ParseReference(repoName, name.WeakValidation)
+	if err != nil {
+		return false, errors.Wrapf(err, "determining whether %s already exists", style.Symbol(repoName))
+	}
+
+	if _, err := remote.Image(ref, remote.WithAuthFromKeychain(keychain)); err != nil {
+		if isNotFoundError(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "determining whether %s already exists", style.Symbol(repoName))
+	}
+	return true, nil
+}
+
+// isNotFoundError reports whether err is a registry response indicating repoName doesn't exist,
+// as opposed to a genuine problem (bad credentials, network failure) reaching the registry.
+func isNotFoundError(err error) bool {
+	transportErr, ok := err.(*transport.Error)
+	if !ok {
+		return false
+	}
+
+	for _, diagnostic := range transportErr.Errors {
+		switch diagnostic.Code {
+		case transport.ManifestUnknownErrorCode, transport.NameUnknownErrorCode:
+			return true
+		}
+	}
+	return false
+}
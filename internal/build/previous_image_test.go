@@ -0,0 +1,84 @@
+package build
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/client"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestIsNotFoundError(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "isNotFoundError", testIsNotFoundError, spec.Report(report.Terminal{}))
+}
+
+func testIsNotFoundError(t *testing.T, when spec.G, it spec.S) {
+	when("the error is a manifest-unknown response", func() {
+		it("returns true", func() {
+			err := &transport.Error{Errors: []transport.Diagnostic{{Code: transport.ManifestUnknownErrorCode}}}
+			h.AssertTrue(t, isNotFoundError(err))
+		})
+	})
+
+	when("the error is a name-unknown response", func() {
+		it("returns true", func() {
+			err := &transport.Error{Errors: []transport.Diagnostic{{Code: transport.NameUnknownErrorCode}}}
+			h.AssertTrue(t, isNotFoundError(err))
+		})
+	})
+
+	when("the error is an unauthorized response", func() {
+		it("returns false", func() {
+			err := &transport.Error{Errors: []transport.Diagnostic{{Code: transport.UnauthorizedErrorCode}}}
+			h.AssertFalse(t, isNotFoundError(err))
+		})
+	})
+
+	when("the error is a denied response", func() {
+		it("returns false", func() {
+			err := &transport.Error{Errors: []transport.Diagnostic{{Code: transport.DeniedErrorCode}}}
+			h.AssertFalse(t, isNotFoundError(err))
+		})
+	})
+
+	when("the error is not a transport error", func() {
+		it("returns false", func() {
+			h.AssertFalse(t, isNotFoundError(errors.New("connection refused")))
+		})
+	})
+}
+
+func TestPreviousImageExists(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "previousImageExists", testPreviousImageExists, spec.Report(report.Terminal{}))
+}
+
+func testPreviousImageExists(t *testing.T, when spec.G, it spec.S) {
+	when("checking the daemon", func() {
+		it("returns an error when the daemon can't be reached", func() {
+			unreachable, err := client.NewClientWithOpts(client.WithHost("tcp://127.0.0.1:1"))
+			h.AssertNil(t, err)
+
+			_, err = previousImageExists(unreachable, authn.DefaultKeychain, "some-image", false)
+			h.AssertNotNil(t, err)
+		})
+	})
+
+	when("checking the registry", func() {
+		it("returns an error when the registry host can't be resolved", func() {
+			_, err := previousImageExists(nil, authn.DefaultKeychain, "this-registry-host-does-not-resolve.invalid/some/image", true)
+			h.AssertNotNil(t, err)
+		})
+	})
+}
@@ -0,0 +1,47 @@
+package build
+
+import (
+	"path"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/project"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// applyProjectDescriptor reads the project descriptor at descriptorPath, if any, and folds its
+// [build] directives into fileFilter and platformFiles. Its exclude patterns become a FileFilter
+// via NewIgnoreFilter, and its env entries become files under env/ alongside platformFiles. An
+// explicit fileFilter always takes precedence over the descriptor's exclude patterns, and an
+// entry already present in platformFiles always takes precedence over the same env/ path derived
+// from the descriptor.
+func applyProjectDescriptor(descriptorPath string, fileFilter func(string) bool, platformFiles map[string][]byte) (func(string) bool, map[string][]byte, error) {
+	if descriptorPath == "" {
+		return fileFilter, platformFiles, nil
+	}
+
+	descriptor, err := project.ReadProjectDescriptor(descriptorPath)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "reading project descriptor %s", style.Symbol(descriptorPath))
+	}
+
+	if fileFilter == nil && len(descriptor.Build.Exclude) > 0 {
+		fileFilter, err = NewIgnoreFilter(descriptor.Build.Exclude)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "building file filter from project descriptor")
+		}
+	}
+
+	if len(descriptor.Build.Env) > 0 {
+		merged := map[string][]byte{}
+		for _, envVar := range descriptor.Build.Env {
+			merged[path.Join("env", envVar.Name)] = []byte(envVar.Value)
+		}
+		for name, contents := range platformFiles {
+			merged[name] = contents
+		}
+		platformFiles = merged
+	}
+
+	return fileFilter, platformFiles, nil
+}
@@ -0,0 +1,143 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func testProjectDescriptorPath(t *testing.T, contents string) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "pack-test-project-descriptor")
+	h.AssertNil(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	descriptorPath := filepath.Join(dir, "project.toml")
+	h.AssertNil(t, ioutil.WriteFile(descriptorPath, []byte(contents), 0644))
+
+	return descriptorPath
+}
+
+func TestApplyProjectDescriptor(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "applyProjectDescriptor", testApplyProjectDescriptor, spec.Report(report.Terminal{}))
+}
+
+func testApplyProjectDescriptor(t *testing.T, when spec.G, it spec.S) {
+	when("#applyProjectDescriptor", func() {
+		it("applies exclude patterns as a FileFilter", func() {
+			descriptorPath := testProjectDescriptorPath(t, `
+[build]
+exclude = ["secrets/"]
+`)
+
+			filter, platformFiles, err := applyProjectDescriptor(descriptorPath, nil, nil)
+			h.AssertNil(t, err)
+			h.AssertNotNil(t, filter)
+			h.AssertFalse(t, filter("secrets/api-key.txt"))
+			h.AssertTrue(t, filter("main.go"))
+			if platformFiles != nil {
+				t.Fatalf("expected no platform files, got %v", platformFiles)
+			}
+		})
+
+		it("prefers an explicit FileFilter over exclude patterns", func() {
+			descriptorPath := testProjectDescriptorPath(t, `
+[build]
+exclude = ["secrets/"]
+`)
+			explicitFilter := func(string) bool { return true }
+
+			filter, _, err := applyProjectDescriptor(descriptorPath, explicitFilter, nil)
+			h.AssertNil(t, err)
+			h.AssertTrue(t, filter("secrets/api-key.txt"))
+		})
+
+		it("applies env entries as platform files", func() {
+			descriptorPath := testProjectDescriptorPath(t, `
+[[build.env]]
+name = "BP_SOME_VAR"
+value = "some-value"
+`)
+
+			_, platformFiles, err := applyProjectDescriptor(descriptorPath, nil, nil)
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(platformFiles["env/BP_SOME_VAR"]), "some-value")
+		})
+
+		it("prefers an explicit platform file over the same env entry", func() {
+			descriptorPath := testProjectDescriptorPath(t, `
+[[build.env]]
+name = "BP_SOME_VAR"
+value = "from-descriptor"
+`)
+
+			_, platformFiles, err := applyProjectDescriptor(descriptorPath, nil, map[string][]byte{
+				"env/BP_SOME_VAR": []byte("from-caller"),
+			})
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(platformFiles["env/BP_SOME_VAR"]), "from-caller")
+		})
+
+		it("returns nothing when no descriptor path is given", func() {
+			filter, platformFiles, err := applyProjectDescriptor("", nil, nil)
+			h.AssertNil(t, err)
+			if filter != nil || platformFiles != nil {
+				t.Fatalf("expected no FileFilter or platform files when no descriptor path is given")
+			}
+		})
+
+		it("errors when the descriptor does not exist", func() {
+			_, _, err := applyProjectDescriptor(filepath.Join(os.TempDir(), "does-not-exist", "project.toml"), nil, nil)
+			h.AssertNotNil(t, err)
+		})
+
+		it("errors when the descriptor is invalid", func() {
+			descriptorPath := testProjectDescriptorPath(t, `
+[build]
+include = ["*.go"]
+exclude = ["secrets/"]
+`)
+
+			_, _, err := applyProjectDescriptor(descriptorPath, nil, nil)
+			h.AssertNotNil(t, err)
+		})
+	})
+
+	when("Setup", func() {
+		it("applies the project descriptor", func() {
+			descriptorPath := testProjectDescriptorPath(t, `
+[build]
+exclude = ["secrets/"]
+
+[[build.env]]
+name = "BP_SOME_VAR"
+value = "some-value"
+`)
+
+			lifecycle := NewLifecycle(nil, nil)
+			h.AssertNil(t, lifecycle.Setup(LifecycleOptions{
+				Builder:               minimalFakeBuilder{},
+				AppPath:               ".",
+				ProjectDescriptorPath: descriptorPath,
+			}))
+
+			h.AssertNotNil(t, lifecycle.fileFilter)
+			h.AssertFalse(t, lifecycle.fileFilter("secrets/api-key.txt"))
+			h.AssertEq(t, string(lifecycle.platformFiles["env/BP_SOME_VAR"]), "some-value")
+			if lifecycle.PlatformVolume == "" {
+				t.Fatalf("expected Setup to allocate a PlatformVolume once platform files are present")
+			}
+		})
+	})
+}
@@ -0,0 +1,44 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestNormalizeNoProxy(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "normalizeNoProxy", testNormalizeNoProxy, spec.Report(report.Terminal{}))
+}
+
+func testNormalizeNoProxy(t *testing.T, when spec.G, it spec.S) {
+	when("NO_PROXY is empty", func() {
+		it("returns it unchanged", func() {
+			h.AssertEq(t, normalizeNoProxy(""), "")
+		})
+	})
+
+	when("entries have surrounding whitespace", func() {
+		it("trims whitespace around commas", func() {
+			h.AssertEq(t, normalizeNoProxy("localhost, 127.0.0.1 ,  registry.internal"), "localhost,127.0.0.1,registry.internal")
+		})
+	})
+
+	when("an entry has a leading wildcard", func() {
+		it("rewrites it to a suffix match", func() {
+			h.AssertEq(t, normalizeNoProxy("*.internal"), ".internal")
+		})
+	})
+
+	when("wildcards are mixed with plain entries", func() {
+		it("rewrites each wildcard to a suffix match", func() {
+			h.AssertEq(t, normalizeNoProxy("localhost, *.internal , *.example.com"), "localhost,.internal,.example.com")
+		})
+	})
+}
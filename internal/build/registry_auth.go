@@ -0,0 +1,56 @@
+package build
+
+import (
+	"regexp"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/pkg/errors"
+)
+
+// registryAuthKeychain resolves credentials from a map of registry host to auth header (e.g.
+// "Basic dXNlcjpwYXNz" or "Bearer asdf="), the same format the lifecycle's CNB_REGISTRY_AUTH
+// environment variable uses. It returns authn.Anonymous for any registry not present in the map,
+// so it's meant to be layered in front of authn.DefaultKeychain via authn.NewMultiKeychain --
+// entries here take priority, and any registry it doesn't recognize falls back to the ambient
+// Docker client config.
+type registryAuthKeychain struct {
+	auths map[string]string
+}
+
+func (k *registryAuthKeychain) Resolve(resource authn.Resource) (authn.Authenticator, error) {
+	header, ok := k.auths[resource.RegistryStr()]
+	if !ok {
+		return authn.Anonymous, nil
+	}
+
+	authConfig, err := authHeaderToConfig(header)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing auth header for registry %s", resource.RegistryStr())
+	}
+	return &providedAuth{config: authConfig}, nil
+}
+
+type providedAuth struct {
+	config *authn.AuthConfig
+}
+
+func (p *providedAuth) Authorization() (*authn.AuthConfig, error) {
+	return p.config, nil
+}
+
+var (
+	basicAuthRegExp  = regexp.MustCompile("(?i)^basic (.*)$")
+	bearerAuthRegExp = regexp.MustCompile("(?i)^bearer (.*)$")
+)
+
+func authHeaderToConfig(header string) (*authn.AuthConfig, error) {
+	if matches := basicAuthRegExp.FindAllStringSubmatch(header, -1); len(matches) != 0 {
+		return &authn.AuthConfig{Auth: matches[0][1]}, nil
+	}
+
+	if matches := bearerAuthRegExp.FindAllStringSubmatch(header, -1); len(matches) != 0 {
+		return &authn.AuthConfig{RegistryToken: matches[0][1]}, nil
+	}
+
+	return nil, errors.Errorf("unknown auth type from header: %s", header)
+}
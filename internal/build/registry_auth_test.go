@@ -0,0 +1,63 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestRegistryAuthKeychain(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "registryAuthKeychain", testRegistryAuthKeychain, spec.Report(report.Terminal{}))
+}
+
+func testRegistryAuthKeychain(t *testing.T, when spec.G, it spec.S) {
+	var keychain *registryAuthKeychain
+
+	it.Before(func() {
+		keychain = &registryAuthKeychain{auths: map[string]string{
+			"my-registry.example.com": "Basic dXNlcjpwYXNz",
+			"gcr.io":                  "Bearer asdf=",
+		}}
+	})
+
+	when("#Resolve", func() {
+		it("resolves a registry with a Basic auth header", func() {
+			authenticator, err := keychain.Resolve(fakeResource{"my-registry.example.com"})
+			h.AssertNil(t, err)
+
+			authConfig, err := authenticator.Authorization()
+			h.AssertNil(t, err)
+			h.AssertEq(t, authConfig.Auth, "dXNlcjpwYXNz")
+		})
+
+		it("resolves a registry with a Bearer auth header", func() {
+			authenticator, err := keychain.Resolve(fakeResource{"gcr.io"})
+			h.AssertNil(t, err)
+
+			authConfig, err := authenticator.Authorization()
+			h.AssertNil(t, err)
+			h.AssertEq(t, authConfig.RegistryToken, "asdf=")
+		})
+
+		it("falls back to anonymous when the registry is not in the map", func() {
+			authenticator, err := keychain.Resolve(fakeResource{"index.docker.io"})
+			h.AssertNil(t, err)
+			h.AssertEq(t, authenticator, authn.Anonymous)
+		})
+	})
+}
+
+type fakeResource struct {
+	registry string
+}
+
+func (r fakeResource) String() string      { return r.registry }
+func (r fakeResource) RegistryStr() string { return r.registry }
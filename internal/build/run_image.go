@@ -0,0 +1,29 @@
+package build
+
+import (
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// selectRunImageMirror returns whichever of runImage or mirrors is hosted on the same registry as
+// targetImage, the way the lifecycle picks a run image mirror at ANALYZING time. It checks
+// runImage itself before any mirror, and falls back to runImage if targetImage can't be parsed or
+// none of the candidates match.
+func selectRunImageMirror(targetImage, runImage string, mirrors []string) string {
+	ref, err := name.ParseReference(targetImage, name.WeakValidation)
+	if err != nil {
+		return runImage
+	}
+	targetRegistry := ref.Context().RegistryStr()
+
+	for _, candidate := range append([]string{runImage}, mirrors...) {
+		candidateRef, err := name.ParseReference(candidate, name.WeakValidation)
+		if err != nil {
+			continue
+		}
+		if candidateRef.Context().RegistryStr() == targetRegistry {
+			return candidate
+		}
+	}
+
+	return runImage
+}
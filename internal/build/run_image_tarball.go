@@ -0,0 +1,89 @@
+package build
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// prepareRunImageTarball loads runImageTarball into the daemon, for air-gapped builds where
+// EXPORTING can't pull runImage from a registry. It's a no-op when runImageTarball is empty,
+// leaving EXPORTING to pull runImage as usual. Call it after Setup, since it validates against
+// l.runImage, the mirror Setup already resolved.
+func (l *Lifecycle) prepareRunImageTarball(ctx context.Context, runImageTarball string) error {
+	if runImageTarball == "" {
+		return nil
+	}
+
+	fh, err := os.Open(runImageTarball)
+	if err != nil {
+		return errors.Wrapf(err, "opening run image tarball %s", style.Symbol(runImageTarball))
+	}
+	defer fh.Close()
+
+	response, err := l.docker.ImageLoad(ctx, fh, true)
+	if err != nil {
+		return errors.Wrapf(err, "loading run image tarball %s", style.Symbol(runImageTarball))
+	}
+	defer response.Body.Close()
+
+	loaded, err := parseLoadedImageNames(response.Body)
+	if err != nil {
+		return errors.Wrapf(err, "reading load response for run image tarball %s", style.Symbol(runImageTarball))
+	}
+
+	return validateLoadedRunImage(runImageTarball, loaded, l.runImage)
+}
+
+// parseLoadedImageNames extracts the image names the daemon reports loading from r, the JSON
+// message stream docker.ImageLoad returns.
+func parseLoadedImageNames(r io.Reader) ([]string, error) {
+	const loadedImagePrefix = "Loaded image: "
+
+	var names []string
+	dec := json.NewDecoder(r)
+	for {
+		var msg struct {
+			Stream string `json:"stream"`
+			Error  string `json:"error"`
+		}
+		if err := dec.Decode(&msg); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		if msg.Error != "" {
+			return nil, errors.New(msg.Error)
+		}
+		if strings.HasPrefix(msg.Stream, loadedImagePrefix) {
+			name := strings.TrimSuffix(strings.TrimPrefix(msg.Stream, loadedImagePrefix), "\n")
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// validateLoadedRunImage returns an error unless expectedRunImage is among the image names
+// loaded from tarballPath, so a run image tarball that doesn't contain the image Setup resolved
+// fails fast instead of letting EXPORTING proceed against whatever happens to already be in the
+// daemon.
+func validateLoadedRunImage(tarballPath string, loaded []string, expectedRunImage string) error {
+	for _, name := range loaded {
+		if name == expectedRunImage {
+			return nil
+		}
+	}
+	return errors.Errorf(
+		"run image tarball %s does not contain %s; it contains %s",
+		style.Symbol(tarballPath),
+		style.Symbol(expectedRunImage),
+		loaded,
+	)
+}
@@ -0,0 +1,77 @@
+package build
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestParseLoadedImageNames(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "parseLoadedImageNames", testParseLoadedImageNames, spec.Report(report.Terminal{}))
+}
+
+func testParseLoadedImageNames(t *testing.T, when spec.G, it spec.S) {
+	when("the stream contains a loaded-image line", func() {
+		it("extracts the tagged image name", func() {
+			stream := `{"stream":"Loaded image: some-registry.io/some/run-image:latest\n"}`
+			names, err := parseLoadedImageNames(strings.NewReader(stream))
+			h.AssertNil(t, err)
+			h.AssertEq(t, names, []string{"some-registry.io/some/run-image:latest"})
+		})
+	})
+
+	when("the stream has no loaded-image line", func() {
+		it("returns no names", func() {
+			stream := `{"stream":"some other progress output\n"}`
+			names, err := parseLoadedImageNames(strings.NewReader(stream))
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(names), 0)
+		})
+	})
+
+	when("the daemon reports an error", func() {
+		it("returns it", func() {
+			stream := `{"error":"unexpected EOF"}`
+			_, err := parseLoadedImageNames(strings.NewReader(stream))
+			h.AssertNotNil(t, err)
+		})
+	})
+}
+
+func TestValidateLoadedRunImage(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "validateLoadedRunImage", testValidateLoadedRunImage, spec.Report(report.Terminal{}))
+}
+
+func testValidateLoadedRunImage(t *testing.T, when spec.G, it spec.S) {
+	when("the tarball contains the expected run image", func() {
+		it("succeeds", func() {
+			err := validateLoadedRunImage("some.tar", []string{"some/run-image:latest"}, "some/run-image:latest")
+			h.AssertNil(t, err)
+		})
+	})
+
+	when("the tarball contains a different image", func() {
+		it("returns an error", func() {
+			err := validateLoadedRunImage("some.tar", []string{"some/other-image:latest"}, "some/run-image:latest")
+			h.AssertNotNil(t, err)
+		})
+	})
+
+	when("the tarball loaded nothing", func() {
+		it("returns an error", func() {
+			err := validateLoadedRunImage("some.tar", nil, "some/run-image:latest")
+			h.AssertNotNil(t, err)
+		})
+	})
+}
@@ -0,0 +1,60 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSelectRunImageMirror(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "selectRunImageMirror", testSelectRunImageMirror, spec.Report(report.Terminal{}))
+}
+
+func testSelectRunImageMirror(t *testing.T, when spec.G, it spec.S) {
+	when("a mirror is hosted on the target registry", func() {
+		it("is preferred", func() {
+			got := selectRunImageMirror(
+				"gcr.io/some/app",
+				"index.docker.io/some/run",
+				[]string{"us.gcr.io/some/run", "gcr.io/some/run"},
+			)
+			h.AssertEq(t, got, "gcr.io/some/run")
+		})
+	})
+
+	when("RunImage itself already matches the target registry", func() {
+		it("is used", func() {
+			got := selectRunImageMirror(
+				"gcr.io/some/app",
+				"gcr.io/some/run",
+				[]string{"us.gcr.io/some/run"},
+			)
+			h.AssertEq(t, got, "gcr.io/some/run")
+		})
+	})
+
+	when("no candidate matches the target registry", func() {
+		it("falls back to RunImage", func() {
+			got := selectRunImageMirror(
+				"gcr.io/some/app",
+				"index.docker.io/some/run",
+				[]string{"us.gcr.io/some/run"},
+			)
+			h.AssertEq(t, got, "index.docker.io/some/run")
+		})
+	})
+
+	when("the target image can't be parsed", func() {
+		it("falls back to RunImage", func() {
+			got := selectRunImageMirror("", "index.docker.io/some/run", []string{"gcr.io/some/run"})
+			h.AssertEq(t, got, "index.docker.io/some/run")
+		})
+	})
+}
@@ -0,0 +1,115 @@
+package build
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/archive"
+)
+
+// ErrSBOMNotFound is returned by ExtractSBOM when the builder's lifecycle didn't write an SBOM
+// under the layers volume -- for example, because it predates lifecycle SBOM support.
+var ErrSBOMNotFound = errors.New("no SBOM was generated for this build")
+
+// ExtractSBOM copies the SBOM files the lifecycle wrote under the layers volume's sbom
+// directory into the host directory dest, preserving their relative paths. Call it against a
+// Lifecycle whose LayersVolume is still present -- for example, one Execute'd with
+// LifecycleOptions.ReuseLayersVolume set, since Cleanup removes any layers volume Execute itself
+// created. It returns ErrSBOMNotFound if the lifecycle didn't produce an SBOM.
+func (l *Lifecycle) ExtractSBOM(ctx context.Context, dest string) error {
+	ctr, err := l.docker.ContainerCreate(ctx, &dcontainer.Config{
+		Image: l.builder.Name(),
+	}, &dcontainer.HostConfig{
+		Binds: []string{fmt.Sprintf("%s:%s", l.LayersVolume, l.paths.layersDir())},
+	}, nil, "")
+	if err != nil {
+		return errors.Wrap(err, "failed to create SBOM extraction container")
+	}
+	defer l.docker.ContainerRemove(context.Background(), ctr.ID, types.ContainerRemoveOptions{Force: true})
+
+	rc, _, err := l.docker.CopyFromContainer(ctx, ctr.ID, l.paths.sbomDir())
+	if err != nil {
+		return ErrSBOMNotFound
+	}
+	defer rc.Close()
+
+	return extractSBOMTar(rc, dest)
+}
+
+// extractSBOMTar writes the contents of r -- a tar stream whose entries are all rooted under a
+// single top-level directory, the shape Docker's CopyFromContainer returns for a directory path
+// -- into dest, stripping that top-level directory so dest itself becomes the sbom directory's
+// contents.
+func extractSBOMTar(r io.Reader, dest string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "reading SBOM archive")
+		}
+
+		if archive.IsUnsafe(header.Name) {
+			return errors.Errorf("SBOM archive entry %s is outside the archive root", header.Name)
+		}
+
+		relPath := stripTopLevelDir(header.Name)
+		if relPath == "" {
+			continue
+		}
+
+		target, err := archive.SafePath(dest, relPath)
+		if err != nil {
+			return errors.Wrapf(err, "SBOM archive entry %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return errors.Wrapf(err, "creating directory %s", target)
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return errors.Wrapf(err, "creating directory %s", filepath.Dir(target))
+			}
+			if err := writeSBOMFile(target, header.Mode, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeSBOMFile(target string, mode int64, r io.Reader) error {
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(mode))
+	if err != nil {
+		return errors.Wrapf(err, "creating file %s", target)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return errors.Wrapf(err, "writing file %s", target)
+	}
+	return nil
+}
+
+// stripTopLevelDir removes the first path segment from name, returning "" if name has no
+// segment beneath it (the top-level directory entry itself).
+func stripTopLevelDir(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
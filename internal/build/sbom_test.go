@@ -0,0 +1,84 @@
+package build
+
+import (
+	"archive/tar"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/archive"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestExtractSBOMTar(t *testing.T) {
+	spec.Run(t, "extractSBOMTar", testExtractSBOMTar, spec.Report(report.Terminal{}))
+}
+
+func testExtractSBOMTar(t *testing.T, when spec.G, it spec.S) {
+	var dest string
+
+	it.Before(func() {
+		var err error
+		dest, err = ioutil.TempDir("", "sbom-extract-test")
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(dest))
+	})
+
+	it("strips the top-level directory and writes nested files", func() {
+		rc := archive.GenerateTar(func(tw *tar.Writer) error {
+			if err := tw.WriteHeader(&tar.Header{Name: "sbom/", Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+				return err
+			}
+			if err := tw.WriteHeader(&tar.Header{Name: "sbom/cdx", Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+				return err
+			}
+			contents := []byte(`{"bomFormat":"CycloneDX"}`)
+			if err := tw.WriteHeader(&tar.Header{
+				Name:     "sbom/cdx/sbom.cdx.json",
+				Typeflag: tar.TypeReg,
+				Mode:     0644,
+				Size:     int64(len(contents)),
+			}); err != nil {
+				return err
+			}
+			_, err := tw.Write(contents)
+			return err
+		})
+		defer rc.Close()
+
+		h.AssertNil(t, extractSBOMTar(rc, dest))
+
+		contents, err := ioutil.ReadFile(filepath.Join(dest, "cdx", "sbom.cdx.json"))
+		h.AssertNil(t, err)
+		h.AssertEq(t, string(contents), `{"bomFormat":"CycloneDX"}`)
+	})
+
+	it("rejects entries that escape the destination directory", func() {
+		rc := archive.GenerateTar(func(tw *tar.Writer) error {
+			return tw.WriteHeader(&tar.Header{
+				Name:     "sbom/../../etc/passwd",
+				Typeflag: tar.TypeReg,
+				Mode:     0644,
+				Size:     0,
+			})
+		})
+		defer rc.Close()
+
+		err := extractSBOMTar(rc, dest)
+		h.AssertError(t, err, "is outside the archive root")
+	})
+}
+
+func TestStripTopLevelDir(t *testing.T) {
+	h.AssertEq(t, stripTopLevelDir("sbom/cdx/sbom.cdx.json"), "cdx/sbom.cdx.json")
+	h.AssertEq(t, stripTopLevelDir("/sbom/cdx/sbom.cdx.json"), "cdx/sbom.cdx.json")
+	h.AssertEq(t, stripTopLevelDir("sbom/"), "")
+	h.AssertEq(t, stripTopLevelDir("sbom"), "")
+}
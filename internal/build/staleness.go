@@ -0,0 +1,45 @@
+package build
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver"
+
+	"github.com/buildpacks/pack/internal/api"
+	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// StaleWarnings compares a builder's embedded lifecycle and Platform API versions, as read by
+// builder.Inspect, against the versions Execute supports, and returns a human-readable warning
+// for each one that's out of date. It's meant for tooling that wants to nag users to rebuild
+// their builders before a build outright fails -- Execute itself still performs its own stricter
+// checks in Setup.
+func StaleWarnings(descriptor builder.LifecycleDescriptor) []string {
+	var warnings []string
+
+	if descriptor.Info.Version != nil {
+		if lifecycleVersion, err := semver.NewVersion(descriptor.Info.Version.String()); err == nil {
+			if lifecycleVersion.LessThan(minimumSupportedLifecycleVersion) {
+				warnings = append(warnings, fmt.Sprintf(
+					"lifecycle version %s is no longer supported; the minimum supported lifecycle version is %s",
+					style.Symbol(descriptor.Info.Version.String()),
+					style.Symbol(minimumSupportedLifecycleVersion.String()),
+				))
+			}
+		}
+	}
+
+	if descriptor.API.PlatformVersion != nil {
+		minimumSupportedPlatformAPI := api.MustParse(SupportedPlatformAPIVersions[0])
+		if descriptor.API.PlatformVersion.Compare(minimumSupportedPlatformAPI) < 0 {
+			warnings = append(warnings, fmt.Sprintf(
+				"platform API %s is below supported minimum %s",
+				style.Symbol(descriptor.API.PlatformVersion.String()),
+				style.Symbol(minimumSupportedPlatformAPI.String()),
+			))
+		}
+	}
+
+	return warnings
+}
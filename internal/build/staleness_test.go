@@ -0,0 +1,53 @@
+package build_test
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/api"
+	"github.com/buildpacks/pack/internal/build"
+	"github.com/buildpacks/pack/internal/builder"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestStaleWarnings(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "StaleWarnings", testStaleWarnings, spec.Report(report.Terminal{}))
+}
+
+func testStaleWarnings(t *testing.T, when spec.G, it spec.S) {
+	when("the lifecycle and platform API are current", func() {
+		it("returns no warnings", func() {
+			warnings := build.StaleWarnings(builder.LifecycleDescriptor{
+				Info: builder.LifecycleInfo{Version: builder.VersionMustParse("0.7.2")},
+				API:  builder.LifecycleAPI{PlatformVersion: api.MustParse("0.3")},
+			})
+			h.AssertEq(t, len(warnings), 0)
+		})
+	})
+
+	when("the lifecycle version is below the minimum supported", func() {
+		it("warns", func() {
+			warnings := build.StaleWarnings(builder.LifecycleDescriptor{
+				Info: builder.LifecycleInfo{Version: builder.VersionMustParse("0.4.0")},
+				API:  builder.LifecycleAPI{PlatformVersion: api.MustParse("0.3")},
+			})
+			h.AssertSliceContainsMatch(t, warnings, "lifecycle version .* is no longer supported")
+		})
+	})
+
+	when("the platform API is below the supported minimum", func() {
+		it("warns", func() {
+			warnings := build.StaleWarnings(builder.LifecycleDescriptor{
+				Info: builder.LifecycleInfo{Version: builder.VersionMustParse("0.7.2")},
+				API:  builder.LifecycleAPI{PlatformVersion: api.MustParse("0.1")},
+			})
+			h.AssertSliceContainsMatch(t, warnings, "platform API .*0.1.* is below supported minimum .*0.2.*")
+		})
+	})
+}
@@ -0,0 +1,58 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestStartPhaseIndex(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "startPhaseIndex", testStartPhaseIndex, spec.Report(report.Terminal{}))
+}
+
+func testStartPhaseIndex(t *testing.T, when spec.G, it spec.S) {
+	when("StartPhase is unset", func() {
+		it("returns the first phase's index", func() {
+			index, err := startPhaseIndex(LifecycleOptions{})
+			h.AssertNil(t, err)
+			h.AssertEq(t, index, 0)
+		})
+	})
+
+	when("StartPhase is set without ReuseLayersVolume", func() {
+		it("returns an error", func() {
+			_, err := startPhaseIndex(LifecycleOptions{StartPhase: "EXPORTING"})
+			h.AssertError(t, err, "requires ReuseLayersVolume")
+		})
+	})
+
+	when("StartPhase is not a recognized phase", func() {
+		it("returns an error", func() {
+			_, err := startPhaseIndex(LifecycleOptions{StartPhase: "BOGUS", ReuseLayersVolume: "some-volume"})
+			h.AssertError(t, err, "unknown start phase")
+		})
+	})
+
+	when("StartPhase is set to a valid phase with ReuseLayersVolume", func() {
+		it("returns that phase's index", func() {
+			index, err := startPhaseIndex(LifecycleOptions{StartPhase: "EXPORTING", ReuseLayersVolume: "some-volume"})
+			h.AssertNil(t, err)
+			h.AssertEq(t, index, 4)
+		})
+	})
+
+	when("StartPhase is set to the first split phase", func() {
+		it("returns index 0", func() {
+			index, err := startPhaseIndex(LifecycleOptions{StartPhase: "DETECTING", ReuseLayersVolume: "some-volume"})
+			h.AssertNil(t, err)
+			h.AssertEq(t, index, 0)
+		})
+	})
+}
@@ -47,6 +47,11 @@ const (
 	envGID = "CNB_GROUP_ID"
 )
 
+// Builder composes a builder image: a base run/build image layered with the CNB lifecycle and
+// one or more buildpacks. Construct one with New or FromImage, configure it with AddBuildpack,
+// SetLifecycle, and the other setters, then call Save to layer everything into the image and
+// write the builder metadata label. This is the inverse of what Lifecycle consumes -- Lifecycle
+// runs the buildpacks a Builder packages.
 type Builder struct {
 	baseImageName        string
 	image                imgutil.Image
@@ -200,11 +205,13 @@ func (b *Builder) GID() int {
 
 // Setters
 
+// AddBuildpack stages bp to be layered into the image on Save.
 func (b *Builder) AddBuildpack(bp dist.Buildpack) {
 	b.additionalBuildpacks = append(b.additionalBuildpacks, bp)
 	b.metadata.Buildpacks = append(b.metadata.Buildpacks, bp.Descriptor().Info)
 }
 
+// SetLifecycle sets the CNB lifecycle binaries Save embeds in the image.
 func (b *Builder) SetLifecycle(lifecycle Lifecycle) error {
 	b.lifecycle = lifecycle
 	b.lifecycleDescriptor = lifecycle.Descriptor()
@@ -233,6 +240,8 @@ func (b *Builder) SetStack(stackConfig builder.StackConfig) {
 	}
 }
 
+// Save layers the lifecycle, staged buildpacks, order, stack, and env into the image and writes
+// the builder metadata label, then saves the image.
 func (b *Builder) Save(logger logging.Logger) error {
 	resolvedOrder, err := processOrder(b.metadata.Buildpacks, b.order)
 	if err != nil {
@@ -274,28 +283,45 @@ func (b *Builder) Save(logger logging.Logger) error {
 		return err
 	}
 
+	// addedDiffIDs tracks the content hash each id@version was added with, so that a leaf
+	// buildpack depended on by more than one meta-buildpack is only layered into the image once,
+	// while still catching two different buildpacks that claim the same id and version.
+	addedDiffIDs := map[string]string{}
+
 	for _, bp := range b.additionalBuildpacks {
+		bpInfo := bp.Descriptor().Info
+
 		bpLayerTar, err := dist.BuildpackToLayerTar(tmpDir, bp)
 		if err != nil {
 			return err
 		}
 
-		if err := b.image.AddLayer(bpLayerTar); err != nil {
+		diffID, err := dist.LayerDiffID(bpLayerTar)
+		if err != nil {
 			return errors.Wrapf(err,
-				"adding layer tar for buildpack %s",
-				style.Symbol(bp.Descriptor().Info.FullName()),
+				"getting content hashes for buildpack %s",
+				style.Symbol(bpInfo.FullName()),
 			)
 		}
 
-		diffID, err := dist.LayerDiffID(bpLayerTar)
-		if err != nil {
+		if existingDiffID, ok := addedDiffIDs[bpInfo.FullName()]; ok {
+			if existingDiffID != diffID.String() {
+				return errors.Errorf(
+					"buildpack %s was provided more than once with differing contents",
+					style.Symbol(bpInfo.FullName()),
+				)
+			}
+			continue
+		}
+		addedDiffIDs[bpInfo.FullName()] = diffID.String()
+
+		if err := b.image.AddLayer(bpLayerTar); err != nil {
 			return errors.Wrapf(err,
-				"getting content hashes for buildpack %s",
-				style.Symbol(bp.Descriptor().Info.FullName()),
+				"adding layer tar for buildpack %s",
+				style.Symbol(bpInfo.FullName()),
 			)
 		}
 
-		bpInfo := bp.Descriptor().Info
 		if _, ok := bpLayers[bpInfo.ID][bpInfo.Version]; ok {
 			logger.Warnf(
 				"buildpack %s already exists on builder and will be overwritten",
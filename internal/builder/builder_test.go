@@ -672,6 +672,56 @@ func testBuilder(t *testing.T, when spec.G, it spec.S) {
 				h.AssertEq(t, layers["order-buildpack-id"]["order-buildpack-version"].Order[0].Group[1].Optional, false)
 			})
 
+			when("the same buildpack is added more than once", func() {
+				it("dedupes identical content and adds only one layer", func() {
+					dup, err := ifakes.NewFakeBuildpack(bp2v1.Descriptor(), 0644)
+					h.AssertNil(t, err)
+					subject.AddBuildpack(dup)
+
+					h.AssertNil(t, subject.Save(logger))
+					h.AssertEq(t, baseImage.IsSaved(), true)
+					assertImageHasBPLayer(t, baseImage, bp2v1)
+
+					withoutDupImage := fakes.NewImage("base/image", "", nil)
+					h.AssertNil(t, withoutDupImage.SetEnv("CNB_USER_ID", "1234"))
+					h.AssertNil(t, withoutDupImage.SetEnv("CNB_GROUP_ID", "4321"))
+					h.AssertNil(t, withoutDupImage.SetLabel("io.buildpacks.stack.id", "some.stack.id"))
+					h.AssertNil(t, withoutDupImage.SetLabel("io.buildpacks.stack.mixins", `["mixinX", "mixinY", "build:mixinA"]`))
+					defer withoutDupImage.Cleanup()
+
+					withoutDupLifecycle := testmocks.NewMockLifecycle(mockController)
+					withoutDupLifecycle.EXPECT().Open().Return(archive.ReadDirAsTar(filepath.Join("testdata", "lifecycle"), ".", 0, 0, 0755, true, nil), nil).AnyTimes()
+					withoutDupLifecycle.EXPECT().Descriptor().Return(mockLifecycle.Descriptor()).AnyTimes()
+
+					withoutDupSubject, err := builder.New(withoutDupImage, "some/builder")
+					h.AssertNil(t, err)
+					h.AssertNil(t, withoutDupSubject.SetLifecycle(withoutDupLifecycle))
+					withoutDupSubject.AddBuildpack(bp1v1)
+					withoutDupSubject.AddBuildpack(bp1v2)
+					withoutDupSubject.AddBuildpack(bp2v1)
+					withoutDupSubject.AddBuildpack(bpOrder)
+					h.AssertNil(t, withoutDupSubject.Save(logger))
+
+					// dup repeats bp2v1's id@version with identical content, so it should add no more
+					// layers than the same builder without it.
+					h.AssertEq(t, baseImage.NumberOfAddedLayers(), withoutDupImage.NumberOfAddedLayers())
+				})
+
+				when("the contents differ", func() {
+					it("errors", func() {
+						// Same descriptor as bp2v1 (so it still passes stack/mixin/API validation), but a
+						// different file mode, so its distribution tar -- and thus its diffID -- differs.
+						conflicting, err := ifakes.NewFakeBuildpack(bp2v1.Descriptor(), 0755)
+						h.AssertNil(t, err)
+
+						subject.AddBuildpack(conflicting)
+
+						err = subject.Save(logger)
+						h.AssertError(t, err, "buildpack 'buildpack-2-id@buildpack-2-version-1' was provided more than once with differing contents")
+					})
+				})
+			})
+
 			when("base image already has buildpack layers label", func() {
 				it.Before(func() {
 					var mdJSON bytes.Buffer
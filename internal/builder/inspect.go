@@ -0,0 +1,56 @@
+package builder
+
+import (
+	"strings"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/dist"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// BuilderInfo describes a builder image's buildpacks, lifecycle, and run image.
+type BuilderInfo struct {
+	Description     string
+	Stack           string
+	Mixins          []string
+	RunImage        string
+	RunImageMirrors []string
+	Buildpacks      []dist.BuildpackInfo
+	Order           dist.Order
+	Lifecycle       LifecycleDescriptor
+	CreatedBy       CreatorMetadata
+}
+
+// Inspect reads image's builder metadata label and returns the buildpacks, lifecycle, and run
+// image it describes, without fetching or running anything else. This mirrors what
+// build.Lifecycle.Setup reads from a builder during Execute, but standalone.
+func Inspect(image imgutil.Image) (BuilderInfo, error) {
+	bldr, err := FromImage(image)
+	if err != nil {
+		return BuilderInfo{}, errors.Wrapf(err, "invalid builder %s", style.Symbol(image.Name()))
+	}
+
+	var commonMixins, buildMixins []string
+	commonMixins = []string{}
+	for _, mixin := range bldr.Mixins() {
+		if strings.HasPrefix(mixin, "build:") {
+			buildMixins = append(buildMixins, mixin)
+		} else {
+			commonMixins = append(commonMixins, mixin)
+		}
+	}
+
+	return BuilderInfo{
+		Description:     bldr.Description(),
+		Stack:           bldr.StackID,
+		Mixins:          append(commonMixins, buildMixins...),
+		RunImage:        bldr.Stack().RunImage.Image,
+		RunImageMirrors: bldr.Stack().RunImage.Mirrors,
+		Buildpacks:      bldr.Buildpacks(),
+		Order:           bldr.Order(),
+		Lifecycle:       bldr.LifecycleDescriptor(),
+		CreatedBy:       bldr.CreatedBy(),
+	}, nil
+}
@@ -0,0 +1,71 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/buildpacks/imgutil/fakes"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/dist"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestInspect(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Inspect", testInspect, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testInspect(t *testing.T, when spec.G, it spec.S) {
+	when("#Inspect", func() {
+		it("reads buildpacks, lifecycle, platform APIs, and run image from the metadata label", func() {
+			builderImage := fakes.NewImage("some/builder", "", nil)
+			h.AssertNil(t, builderImage.SetLabel("io.buildpacks.stack.id", "test.stack.id"))
+			h.AssertNil(t, builderImage.SetEnv("CNB_USER_ID", "1234"))
+			h.AssertNil(t, builderImage.SetEnv("CNB_GROUP_ID", "4321"))
+			h.AssertNil(t, builderImage.SetLabel("io.buildpacks.builder.metadata", `{
+  "description": "Some description",
+  "stack": {
+    "runImage": {
+      "image": "some/run-image",
+      "mirrors": ["gcr.io/some/default"]
+    }
+  },
+  "buildpacks": [
+    {"id": "test.bp.one", "version": "1.0.0"}
+  ],
+  "lifecycle": {
+    "version": "1.2.3",
+    "api": {"buildpack": "1.2", "platform": "0.3"}
+  },
+  "createdBy": {"name": "pack", "version": "1.2.3"}
+}`))
+			h.AssertNil(t, builderImage.SetLabel(
+				"io.buildpacks.buildpack.order",
+				`[{"group": [{"id": "buildpack-1-id", "optional": false}]}]`,
+			))
+
+			info, err := builder.Inspect(builderImage)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, info.Description, "Some description")
+			h.AssertEq(t, info.Stack, "test.stack.id")
+			h.AssertEq(t, info.RunImage, "some/run-image")
+			h.AssertEq(t, info.RunImageMirrors, []string{"gcr.io/some/default"})
+			h.AssertEq(t, info.Buildpacks, []dist.BuildpackInfo{{ID: "test.bp.one", Version: "1.0.0"}})
+			h.AssertEq(t, info.Lifecycle.Info.Version.String(), "1.2.3")
+			h.AssertEq(t, info.Lifecycle.API.PlatformVersion.String(), "0.3")
+			h.AssertEq(t, info.CreatedBy.Name, "pack")
+		})
+
+		it("returns an error for an image with no builder metadata label", func() {
+			builderImage := fakes.NewImage("some/builder", "", nil)
+
+			_, err := builder.Inspect(builderImage)
+			h.AssertError(t, err, "invalid builder 'some/builder'")
+		})
+	})
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"os"
+
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+)
+
+// BindCache is a Cache backed by a host directory bind mount rather than a Docker named volume.
+// It implements the same Cache interface as VolumeCache so callers can swap between the two
+// without changing how the cache is passed to the lifecycle containers: Docker's bind syntax
+// treats an absolute path on the left-hand side of a "-v" bind as a host directory instead of a
+// named volume.
+type BindCache struct {
+	path   string
+	kind   string
+	docker client.CommonAPIClient
+}
+
+// NewBindCache returns a Cache backed by the host directory at path. kind identifies the cache's
+// purpose (e.g. "build" or "launch") and is kept for parity with NewVolumeCache; dockerClient is
+// accepted for the same reason and so a future implementation can manage the directory through
+// Docker (e.g. for remote daemons) without changing this constructor's signature.
+func NewBindCache(path, kind string, dockerClient client.CommonAPIClient) *BindCache {
+	return &BindCache{
+		path:   path,
+		kind:   kind,
+		docker: dockerClient,
+	}
+}
+
+func (c *BindCache) Name() string {
+	return c.path
+}
+
+// Type returns the cache's kind, e.g. "build" or "launch".
+func (c *BindCache) Type() string {
+	return c.kind
+}
+
+func (c *BindCache) Clear(ctx context.Context) error {
+	if err := os.RemoveAll(c.path); err != nil {
+		return errors.Wrapf(err, "clearing %s cache directory %s", c.kind, c.path)
+	}
+	return os.MkdirAll(c.path, 0755)
+}
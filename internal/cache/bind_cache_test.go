@@ -0,0 +1,72 @@
+package cache_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/cache"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBindCache(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "BindCache", testBindCache, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBindCache(t *testing.T, when spec.G, it spec.S) {
+	when("#Name", func() {
+		it("returns the host directory path", func() {
+			subject := cache.NewBindCache("/tmp/some-cache-dir", "build", nil)
+			h.AssertEq(t, subject.Name(), "/tmp/some-cache-dir")
+		})
+	})
+
+	when("#Type", func() {
+		it("returns the kind it was constructed with", func() {
+			subject := cache.NewBindCache("/tmp/some-cache-dir", "launch", nil)
+			h.AssertEq(t, subject.Type(), "launch")
+		})
+	})
+
+	when("#Clear", func() {
+		var dir string
+
+		it.Before(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "pack-bind-cache-test")
+			h.AssertNil(t, err)
+		})
+
+		it.After(func() {
+			os.RemoveAll(dir)
+		})
+
+		it("removes the cache directory's contents", func() {
+			path := filepath.Join(dir, "cache")
+			h.AssertNil(t, os.MkdirAll(path, 0755))
+			h.AssertNil(t, ioutil.WriteFile(filepath.Join(path, "some-file"), []byte("contents"), 0644))
+
+			subject := cache.NewBindCache(path, "build", nil)
+			h.AssertNil(t, subject.Clear(context.TODO()))
+
+			entries, err := ioutil.ReadDir(path)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(entries), 0)
+		})
+
+		it("does not fail if the cache directory does not exist", func() {
+			path := filepath.Join(dir, "does-not-exist")
+			subject := cache.NewBindCache(path, "build", nil)
+			h.AssertNil(t, subject.Clear(context.TODO()))
+		})
+	})
+}
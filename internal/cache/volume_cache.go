@@ -4,28 +4,82 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"time"
 
 	"github.com/docker/docker/client"
 	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
 )
 
+// errVolumeUsageUnavailable is returned by Size when the volume driver does not report disk usage.
+var errVolumeUsageUnavailable = fmt.Errorf("volume usage data is not available")
+
 type VolumeCache struct {
-	docker client.CommonAPIClient
-	volume string
+	docker    client.CommonAPIClient
+	volume    string
+	kind      string
+	namespace string
+}
+
+// VolumeCacheOption customizes the construction of a VolumeCache by NewVolumeCache.
+type VolumeCacheOption func(*VolumeCache)
+
+// WithVolumeName overrides the volume name that would otherwise be derived from the image
+// reference. An empty name is ignored, leaving the derived name in place.
+func WithVolumeName(name string) VolumeCacheOption {
+	return func(c *VolumeCache) {
+		if name != "" {
+			c.volume = name
+		}
+	}
+}
+
+// WithNamespace overrides the daemon identity NewVolumeCache otherwise derives from
+// dockerClient.DaemonHost() when deriving the volume name. An empty namespace is ignored, leaving
+// the derived one in place.
+func WithNamespace(namespace string) VolumeCacheOption {
+	return func(c *VolumeCache) {
+		if namespace != "" {
+			c.namespace = namespace
+		}
+	}
 }
 
-func NewVolumeCache(imageRef name.Reference, suffix string, dockerClient client.CommonAPIClient) *VolumeCache {
-	sum := sha256.Sum256([]byte(imageRef.Name()))
-	return &VolumeCache{
-		volume: fmt.Sprintf("pack-cache-%x.%s", sum[:6], suffix),
+// NewVolumeCache returns a Cache backed by a Docker named volume derived from imageRef and
+// suffix (e.g. "build" or "launch"). The derived name also incorporates dockerClient's
+// DaemonHost(), so switching DOCKER_HOST between builds resolves to a different volume instead of
+// silently missing the cache a different daemon created. Use WithVolumeName to bypass derivation
+// entirely, or WithNamespace to override the daemon identity used in it.
+func NewVolumeCache(imageRef name.Reference, suffix string, dockerClient client.CommonAPIClient, ops ...VolumeCacheOption) *VolumeCache {
+	c := &VolumeCache{
 		docker: dockerClient,
+		kind:   suffix,
 	}
+	if dockerClient != nil {
+		c.namespace = dockerClient.DaemonHost()
+	}
+
+	for _, op := range ops {
+		op(c)
+	}
+
+	if c.volume == "" {
+		sum := sha256.Sum256([]byte(c.namespace + imageRef.Name()))
+		c.volume = fmt.Sprintf("pack-cache-%x.%s", sum[:6], suffix)
+	}
+
+	return c
 }
 
 func (c *VolumeCache) Name() string {
 	return c.volume
 }
 
+// Type returns the cache's kind, e.g. "build" or "launch".
+func (c *VolumeCache) Type() string {
+	return c.kind
+}
+
 func (c *VolumeCache) Clear(ctx context.Context) error {
 	err := c.docker.VolumeRemove(ctx, c.Name(), true)
 	if err != nil && !client.IsErrNotFound(err) {
@@ -33,3 +87,48 @@ func (c *VolumeCache) Clear(ctx context.Context) error {
 	}
 	return nil
 }
+
+// Size returns the amount of disk space used by the volume, in bytes, as reported by the
+// Docker volume driver.
+func (c *VolumeCache) Size(ctx context.Context) (int64, error) {
+	volume, err := c.docker.VolumeInspect(ctx, c.Name())
+	if err != nil {
+		return 0, err
+	}
+
+	if volume.UsageData == nil {
+		return 0, errVolumeUsageUnavailable
+	}
+
+	return volume.UsageData.Size, nil
+}
+
+// Prune removes the cache volume if it has not been (re)created within olderThan.
+//
+// The Docker volume API does not expose per-file access times, and the cache's contents are
+// written entirely by the lifecycle binary running inside the build container, not by pack. So
+// the only age pack can observe is the volume's own CreatedAt timestamp, and Prune necessarily
+// evicts the whole cache rather than individual stale layers. Tracking true per-layer access
+// times would require the lifecycle itself to persist that metadata inside the volume (for
+// example, a "<layer-sha>.last-used" sidecar file written next to each cached layer) which is
+// outside of what pack controls today.
+func (c *VolumeCache) Prune(ctx context.Context, olderThan time.Duration) error {
+	volume, err := c.docker.VolumeInspect(ctx, c.Name())
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	createdAt, err := time.Parse(time.RFC3339, volume.CreatedAt)
+	if err != nil {
+		return errors.Wrapf(err, "parsing created time for volume %s", c.Name())
+	}
+
+	if time.Since(createdAt) > olderThan {
+		return c.Clear(ctx)
+	}
+
+	return nil
+}
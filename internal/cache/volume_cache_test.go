@@ -47,6 +47,46 @@ func testCache(t *testing.T, when spec.G, it spec.S) {
 			}
 		})
 
+		it("overrides the calculated name when WithVolumeName is given", func() {
+			ref, err := name.ParseReference("my/repo", name.WeakValidation)
+			h.AssertNil(t, err)
+			subject := cache.NewVolumeCache(ref, "some-suffix", dockerClient, cache.WithVolumeName("my-custom-name"))
+			h.AssertEq(t, subject.Name(), "my-custom-name")
+		})
+
+		it("falls back to the calculated name when WithVolumeName is empty", func() {
+			ref, err := name.ParseReference("my/repo", name.WeakValidation)
+			h.AssertNil(t, err)
+			subject := cache.NewVolumeCache(ref, "some-suffix", dockerClient, cache.WithVolumeName(""))
+			expected := cache.NewVolumeCache(ref, "some-suffix", dockerClient)
+			h.AssertEq(t, subject.Name(), expected.Name())
+		})
+
+		it("scopes the calculated name to the daemon it was constructed with", func() {
+			ref, err := name.ParseReference("my/repo", name.WeakValidation)
+			h.AssertNil(t, err)
+			subject := cache.NewVolumeCache(ref, "some-suffix", dockerClient, cache.WithNamespace("tcp://remote-daemon:2376"))
+			notExpected := cache.NewVolumeCache(ref, "some-suffix", dockerClient, cache.WithNamespace("tcp://other-daemon:2376"))
+			if subject.Name() == notExpected.Name() {
+				t.Fatalf("Different namespaces should result in different volumes")
+			}
+		})
+
+		it("ignores an empty WithNamespace, falling back to the derived daemon identity", func() {
+			ref, err := name.ParseReference("my/repo", name.WeakValidation)
+			h.AssertNil(t, err)
+			subject := cache.NewVolumeCache(ref, "some-suffix", dockerClient, cache.WithNamespace(""))
+			expected := cache.NewVolumeCache(ref, "some-suffix", dockerClient)
+			h.AssertEq(t, subject.Name(), expected.Name())
+		})
+
+		it("reports the suffix as its Type", func() {
+			ref, err := name.ParseReference("my/repo", name.WeakValidation)
+			h.AssertNil(t, err)
+			subject := cache.NewVolumeCache(ref, "some-suffix", dockerClient)
+			h.AssertEq(t, subject.Type(), "some-suffix")
+		})
+
 		it("reusing the same cache for the same repo name", func() {
 			ref, err := name.ParseReference("my/repo", name.WeakValidation)
 			h.AssertNil(t, err)
@@ -152,4 +192,102 @@ func testCache(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 	})
+
+	when("#Size", func() {
+		var (
+			dockerClient client.CommonAPIClient
+			subject      *cache.VolumeCache
+			ctx          context.Context
+		)
+
+		it.Before(func() {
+			var err error
+			dockerClient, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
+			h.AssertNil(t, err)
+			ctx = context.TODO()
+
+			ref, err := name.ParseReference(h.RandString(10), name.WeakValidation)
+			h.AssertNil(t, err)
+			subject = cache.NewVolumeCache(ref, "some-suffix", dockerClient)
+		})
+
+		when("there is no cache volume", func() {
+			it("returns an error", func() {
+				_, err := subject.Size(ctx)
+				h.AssertError(t, err, "Error: No such volume")
+			})
+		})
+	})
+
+	when("#Prune", func() {
+		var (
+			dockerClient client.CommonAPIClient
+			subject      *cache.VolumeCache
+			ctx          context.Context
+		)
+
+		it.Before(func() {
+			var err error
+			dockerClient, err = client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.38"))
+			h.AssertNil(t, err)
+			ctx = context.TODO()
+
+			ref, err := name.ParseReference(h.RandString(10), name.WeakValidation)
+			h.AssertNil(t, err)
+			subject = cache.NewVolumeCache(ref, "some-suffix", dockerClient)
+		})
+
+		when("there is no cache volume", func() {
+			it("does not fail", func() {
+				err := subject.Prune(ctx, time.Hour)
+				h.AssertNil(t, err)
+			})
+		})
+
+		when("the cache volume is newer than the given age", func() {
+			it.Before(func() {
+				_, err := dockerClient.VolumeCreate(context.TODO(), volume.VolumeCreateBody{
+					Name: subject.Name(),
+				})
+				h.AssertNil(t, err)
+			})
+
+			it.After(func() {
+				subject.Clear(ctx)
+			})
+
+			it("keeps the volume", func() {
+				err := subject.Prune(ctx, time.Hour)
+				h.AssertNil(t, err)
+
+				volumes, err := dockerClient.VolumeList(context.TODO(), filters.NewArgs(filters.KeyValuePair{
+					Key:   "name",
+					Value: subject.Name(),
+				}))
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(volumes.Volumes), 1)
+			})
+		})
+
+		when("the cache volume is older than the given age", func() {
+			it.Before(func() {
+				_, err := dockerClient.VolumeCreate(context.TODO(), volume.VolumeCreateBody{
+					Name: subject.Name(),
+				})
+				h.AssertNil(t, err)
+			})
+
+			it("removes the volume", func() {
+				err := subject.Prune(ctx, -time.Hour)
+				h.AssertNil(t, err)
+
+				volumes, err := dockerClient.VolumeList(context.TODO(), filters.NewArgs(filters.KeyValuePair{
+					Key:   "name",
+					Value: subject.Name(),
+				}))
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(volumes.Volumes), 0)
+			})
+		})
+	})
 }
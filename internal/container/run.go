@@ -36,10 +36,19 @@ func Run(ctx context.Context, docker client.CommonAPIClient, ctrID string, out,
 	select {
 	case body := <-bodyChan:
 		if body.StatusCode != 0 {
-			return fmt.Errorf("failed with status code: %d", body.StatusCode)
+			return &ExitError{ExitCode: int(body.StatusCode)}
 		}
 	case err := <-errChan:
 		return err
 	}
 	return <-copyErr
 }
+
+// ExitError is returned by Run when the container exits with a non-zero status code.
+type ExitError struct {
+	ExitCode int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("failed with status code: %d", e.ExitCode)
+}
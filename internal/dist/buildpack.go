@@ -2,8 +2,13 @@ package dist
 
 import (
 	"archive/tar"
+	"bytes"
 	"io"
+	"io/ioutil"
 	"path"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/pkg/errors"
@@ -11,11 +16,15 @@ import (
 	"github.com/buildpacks/pack/internal/api"
 	"github.com/buildpacks/pack/internal/archive"
 	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
 )
 
 const AssumedBuildpackAPIVersion = "0.1"
 const BuildpacksDir = "/cnb/buildpacks"
 
+// SupportedBuildpackAPIVersions lists the buildpack API versions pack supports.
+var SupportedBuildpackAPIVersions = []string{"0.1", "0.2", "0.3"}
+
 type Blob interface {
 	// Open returns a io.ReadCloser for the contents of the Blob in tar format.
 	Open() (io.ReadCloser, error)
@@ -40,9 +49,10 @@ type Buildpack interface {
 }
 
 type BuildpackInfo struct {
-	ID       string `toml:"id" json:"id,omitempty"`
-	Version  string `toml:"version" json:"version,omitempty"`
-	Homepage string `toml:"homepage,omitempty" json:"homepage,omitempty"`
+	ID          string `toml:"id" json:"id,omitempty"`
+	Version     string `toml:"version" json:"version,omitempty"`
+	Homepage    string `toml:"homepage,omitempty" json:"homepage,omitempty"`
+	Description string `toml:"description,omitempty" json:"description,omitempty"`
 }
 
 func (b BuildpackInfo) FullName() string {
@@ -60,6 +70,9 @@ func (b BuildpackInfo) Match(o BuildpackInfo) bool {
 	return b.ID == o.ID && b.Version == o.Version
 }
 
+// Stack identifies a stack a buildpack supports. IDs are compared case-sensitively; a stack ID
+// parsed from a buildpack.toml by BuildpackFromRootBlob has any leading/trailing whitespace
+// trimmed first, but is otherwise matched exactly against the builder's stack ID.
 type Stack struct {
 	ID     string   `json:"id"`
 	Mixins []string `json:"mixins,omitempty"`
@@ -74,10 +87,75 @@ func BuildpackFromBlob(bpd BuildpackDescriptor, blob Blob) Buildpack {
 	}
 }
 
+// BuildpackRootBlobOption customizes the construction of a Buildpack by BuildpackFromRootBlob.
+type BuildpackRootBlobOption func(*rootBlobOptions)
+
+type rootBlobOptions struct {
+	buildpacksDir       string
+	logger              logging.Logger
+	fileModeFunc        func(*tar.Header) int64
+	normalizedTimestamp time.Time
+	laxParsing          bool
+}
+
+// PreserveModTime, passed to WithNormalizedTimestamp, leaves each tar entry's original mod time
+// untouched instead of normalizing it to a fixed value.
+var PreserveModTime = time.Time{}
+
+// WithBuildpacksDir overrides the '/cnb/buildpacks' prefix normally used when translating a
+// buildpack blob to distribution format.
+func WithBuildpacksDir(dir string) BuildpackRootBlobOption {
+	return func(o *rootBlobOptions) {
+		o.buildpacksDir = dir
+	}
+}
+
+// WithLogger supplies a logger that BuildpackFromRootBlob uses to warn about deprecated
+// buildpack.toml constructs, such as relying on the default API version.
+func WithLogger(logger logging.Logger) BuildpackRootBlobOption {
+	return func(o *rootBlobOptions) {
+		o.logger = logger
+	}
+}
+
+// WithFileModeFunc overrides the function used to normalize file modes when translating a
+// buildpack blob to distribution format. By default, directories and 'bin/detect'/'bin/build'
+// are normalized to 0755, any file with an exec bit set is normalized to 0755, symlinks keep
+// their original mode, and everything else is normalized to 0644.
+func WithFileModeFunc(fileModeFunc func(*tar.Header) int64) BuildpackRootBlobOption {
+	return func(o *rootBlobOptions) {
+		o.fileModeFunc = fileModeFunc
+	}
+}
+
+// WithNormalizedTimestamp overrides the mod time that BuildpackFromRootBlob normalizes tar
+// entries to when translating a buildpack blob to distribution format. Pass PreserveModTime to
+// leave each entry's original mod time untouched instead -- useful when debugging a buildpack or
+// honoring a caller-supplied SOURCE_DATE_EPOCH. Defaults to archive.NormalizedDateTime.
+func WithNormalizedTimestamp(ts time.Time) BuildpackRootBlobOption {
+	return func(o *rootBlobOptions) {
+		o.normalizedTimestamp = ts
+	}
+}
+
+// WithLaxParsing makes BuildpackFromRootBlob collect every descriptor validation problem into a
+// single error instead of returning only the first one it finds. Meant for authoring tooling that
+// wants to report everything wrong with a buildpack.toml at once.
+func WithLaxParsing() BuildpackRootBlobOption {
+	return func(o *rootBlobOptions) {
+		o.laxParsing = true
+	}
+}
+
 // BuildpackFromRootBlob constructs a buildpack from a blob. It is assumed that the buildpack contents reside at the
 // root of the blob. The constructed buildpack contents will be structured as per the distribution spec (currently
 // a tar with contents under '/cnbs/buildpacks/{ID}/{version}/*').
-func BuildpackFromRootBlob(blob Blob) (Buildpack, error) {
+func BuildpackFromRootBlob(blob Blob, ops ...BuildpackRootBlobOption) (Buildpack, error) {
+	opts := rootBlobOptions{buildpacksDir: BuildpacksDir, fileModeFunc: calcFileMode, normalizedTimestamp: archive.NormalizedDateTime}
+	for _, op := range ops {
+		op(&opts)
+	}
+
 	bpd := BuildpackDescriptor{}
 	rc, err := blob.Open()
 	if err != nil {
@@ -85,18 +163,42 @@ func BuildpackFromRootBlob(blob Blob) (Buildpack, error) {
 	}
 	defer rc.Close()
 
-	_, buf, err := archive.ReadTarEntry(rc, "buildpack.toml")
+	buf, entries, err := readBuildpackDescriptorBlob(rc)
 	if err != nil {
 		return nil, errors.Wrap(err, "reading buildpack.toml")
 	}
 
 	bpd.API = api.MustParse(AssumedBuildpackAPIVersion)
-	_, err = toml.Decode(string(buf), &bpd)
+	md, err := toml.Decode(string(buf), &bpd)
 	if err != nil {
 		return nil, errors.Wrap(err, "decoding buildpack.toml")
 	}
 
-	err = validateDescriptor(bpd)
+	if err := resolveIncludes(&bpd, buf, entries, map[string]bool{}); err != nil {
+		return nil, errors.Wrap(err, "resolving buildpack.toml")
+	}
+
+	if !md.IsDefined("api") && opts.logger != nil {
+		opts.logger.Warnf(
+			"Buildpack %s is missing %s. It will be assumed to have Buildpack API version %s. This default will be removed in a future release.",
+			style.Symbol(bpd.Info.FullName()),
+			style.Symbol("api"),
+			style.Symbol(AssumedBuildpackAPIVersion),
+		)
+	}
+
+	// Generated buildpack.toml files occasionally carry trailing whitespace on a stack id, which
+	// causes later comparisons against a builder's stack id to fail confusingly. Stack ids remain
+	// case-sensitive -- only surrounding whitespace is trimmed.
+	for i := range bpd.Stacks {
+		bpd.Stacks[i].ID = strings.TrimSpace(bpd.Stacks[i].ID)
+	}
+
+	if opts.laxParsing {
+		err = bpd.ValidateAll()
+	} else {
+		err = bpd.Validate()
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "invalid buildpack.toml")
 	}
@@ -106,13 +208,113 @@ func BuildpackFromRootBlob(blob Blob) (Buildpack, error) {
 		Blob: &distBlob{
 			openFn: func() io.ReadCloser {
 				return archive.GenerateTar(func(tw *tar.Writer) error {
-					return toDistTar(tw, bpd, blob)
+					return toDistTar(tw, bpd, blob, opts.buildpacksDir, opts.fileModeFunc, opts.normalizedTimestamp)
 				})
 			},
 		},
 	}, nil
 }
 
+// resolveIncludes merges the order groups declared by an "include = '<path>.toml'" directive
+// (and, recursively, by that included file's own "include" directive) into bpd.Order, so a
+// meta-buildpack can factor a shared set of order groups out into its own file instead of
+// repeating it across every buildpack.toml that needs it. Include paths are resolved relative to
+// the blob root, against entries, which indexes every file in the blob by cleaned path. visited
+// tracks the include chain seen so far, so a cycle is reported clearly instead of recursing
+// forever. A buildpack.toml with no "include" key is left untouched.
+func resolveIncludes(bpd *BuildpackDescriptor, buf []byte, entries map[string][]byte, visited map[string]bool) error {
+	var holder struct {
+		Include string `toml:"include"`
+	}
+	if _, err := toml.Decode(string(buf), &holder); err != nil {
+		return err
+	}
+	if holder.Include == "" {
+		return nil
+	}
+
+	includePath := path.Clean(holder.Include)
+	if visited[includePath] {
+		return errors.Errorf("cycle detected while resolving %s", style.Symbol(holder.Include))
+	}
+	visited[includePath] = true
+
+	includeBuf, ok := entries[includePath]
+	if !ok {
+		return errors.Errorf("include %s: no such file", style.Symbol(holder.Include))
+	}
+
+	var included BuildpackDescriptor
+	if _, err := toml.Decode(string(includeBuf), &included); err != nil {
+		return errors.Wrapf(err, "decoding %s", style.Symbol(holder.Include))
+	}
+
+	bpd.Order = append(bpd.Order, included.Order...)
+
+	return resolveIncludes(bpd, includeBuf, entries, visited)
+}
+
+// BuildpackFromOCILayoutBlob constructs one Buildpack per descriptor found in an exploded OCI
+// layout blob (marked by an "oci-layout" file at its root), preserving every version of a
+// buildpack id packaged as separate layers. Each returned Buildpack's Open() produces a tar
+// scoped to that descriptor's own '/cnb/buildpacks/{ID}/{version}' path, built only from the
+// contents of the layer it was found in.
+func BuildpackFromOCILayoutBlob(blob Blob) ([]Buildpack, error) {
+	rc, err := blob.Open()
+	if err != nil {
+		return nil, errors.Wrap(err, "open buildpack")
+	}
+	defer rc.Close()
+
+	entries, err := readOCILayoutBlob(rc)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading oci layout")
+	}
+
+	descriptors, err := enumerateOCILayoutDescriptors(entries)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading oci layout")
+	}
+	if len(descriptors) == 0 {
+		return nil, errors.Wrap(errors.Wrapf(archive.ErrEntryNotExist, "could not find entry path '%s'", "buildpack.toml"), "reading buildpack.toml")
+	}
+
+	var buildpacks []Buildpack
+	for _, d := range descriptors {
+		bpd := BuildpackDescriptor{API: api.MustParse(AssumedBuildpackAPIVersion)}
+		if _, err := toml.Decode(string(d.descriptorBytes), &bpd); err != nil {
+			return nil, errors.Wrap(err, "decoding buildpack.toml")
+		}
+
+		if err := bpd.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid buildpack.toml")
+		}
+
+		layerBlob := &rawTarBlob{tarBytes: d.tarBytes}
+		buildpacks = append(buildpacks, &buildpack{
+			descriptor: bpd,
+			Blob: &distBlob{
+				openFn: func() io.ReadCloser {
+					return archive.GenerateTar(func(tw *tar.Writer) error {
+						return toDistTar(tw, bpd, layerBlob, BuildpacksDir, calcFileMode, archive.NormalizedDateTime)
+					})
+				},
+			},
+		})
+	}
+
+	return buildpacks, nil
+}
+
+// rawTarBlob is a Blob backed by tar contents already held in memory.
+type rawTarBlob struct {
+	tarBytes []byte
+}
+
+func (b *rawTarBlob) Open() (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(b.tarBytes)), nil
+}
+
 type distBlob struct {
 	openFn func() io.ReadCloser
 }
@@ -121,19 +323,20 @@ func (b *distBlob) Open() (io.ReadCloser, error) {
 	return b.openFn(), nil
 }
 
-func toDistTar(tw *tar.Writer, bpd BuildpackDescriptor, blob Blob) error {
-	ts := archive.NormalizedDateTime
+func toDistTar(tw *tar.Writer, bpd BuildpackDescriptor, blob Blob, buildpacksDir string, fileModeFunc func(*tar.Header) int64, normalizedTimestamp time.Time) error {
+	ts := normalizedTimestamp
+	preserveModTime := ts.IsZero()
 
 	if err := tw.WriteHeader(&tar.Header{
 		Typeflag: tar.TypeDir,
-		Name:     path.Join(BuildpacksDir, bpd.EscapedID()),
+		Name:     path.Join(buildpacksDir, bpd.EscapedID()),
 		Mode:     0755,
 		ModTime:  ts,
 	}); err != nil {
 		return errors.Wrapf(err, "writing buildpack id dir header")
 	}
 
-	baseTarDir := path.Join(BuildpacksDir, bpd.EscapedID(), bpd.Info.Version)
+	baseTarDir := path.Join(buildpacksDir, bpd.EscapedID(), bpd.Info.Version)
 	if err := tw.WriteHeader(&tar.Header{
 		Typeflag: tar.TypeDir,
 		Name:     baseTarDir,
@@ -149,6 +352,7 @@ func toDistTar(tw *tar.Writer, bpd BuildpackDescriptor, blob Blob) error {
 	}
 	defer rc.Close()
 
+	var entries []distTarEntry
 	tr := tar.NewReader(rc)
 	for {
 		header, err := tr.Next()
@@ -159,30 +363,61 @@ func toDistTar(tw *tar.Writer, bpd BuildpackDescriptor, blob Blob) error {
 			return errors.Wrap(err, "failed to get next tar entry")
 		}
 
-		archive.NormalizeHeader(header, true)
+		archive.NormalizeHeader(header, false)
+		if !preserveModTime {
+			header.ModTime = ts
+		}
 		header.Name = path.Clean(header.Name)
 		if header.Name == "." || header.Name == "/" {
 			continue
 		}
 
-		header.Mode = calcFileMode(header)
+		header.Mode = fileModeFunc(header)
 		header.Name = path.Join(baseTarDir, header.Name)
-		err = tw.WriteHeader(header)
-		if err != nil {
-			return errors.Wrapf(err, "failed to write header for '%s'", header.Name)
+		if header.Name != baseTarDir && !strings.HasPrefix(header.Name, baseTarDir+"/") {
+			return errors.Errorf("buildpack %s contains entry that escapes its root: %s",
+				style.Symbol(bpd.Info.FullName()), style.Symbol(header.Name))
 		}
 
-		_, err = io.Copy(tw, tr)
+		content, err := ioutil.ReadAll(tr)
 		if err != nil {
-			return errors.Wrapf(err, "failed to write contents to '%s'", header.Name)
+			return errors.Wrapf(err, "failed to read contents of '%s'", header.Name)
+		}
+
+		entries = append(entries, distTarEntry{header: header, content: content})
+	}
+
+	// Sort entries lexicographically by name so that two runs over equivalent, but differently
+	// ordered, content (e.g. due to filesystem iteration order) produce byte-for-byte identical
+	// tars.
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].header.Name < entries[j].header.Name
+	})
+
+	for _, entry := range entries {
+		if err := tw.WriteHeader(entry.header); err != nil {
+			return errors.Wrapf(err, "failed to write header for '%s'", entry.header.Name)
+		}
+
+		if _, err := tw.Write(entry.content); err != nil {
+			return errors.Wrapf(err, "failed to write contents to '%s'", entry.header.Name)
 		}
 	}
 
 	return nil
 }
 
+type distTarEntry struct {
+	header  *tar.Header
+	content []byte
+}
+
 func calcFileMode(header *tar.Header) int64 {
 	switch {
+	case header.Typeflag == tar.TypeSymlink:
+		// Permission bits on a symlink are meaningless; leave them untouched so the
+		// link (and its target, stored in Linkname) survive translation unmodified.
+		return header.Mode
 	case header.Typeflag == tar.TypeDir:
 		return 0755
 	case nameOneOf(header.Name,
@@ -210,32 +445,11 @@ func anyExecBit(mode int64) bool {
 	return mode&0111 != 0
 }
 
-func validateDescriptor(bpd BuildpackDescriptor) error {
-	if bpd.Info.ID == "" {
-		return errors.Errorf("%s is required", style.Symbol("buildpack.id"))
-	}
-
-	if bpd.Info.Version == "" {
-		return errors.Errorf("%s is required", style.Symbol("buildpack.version"))
-	}
-
-	if len(bpd.Order) == 0 && len(bpd.Stacks) == 0 {
-		return errors.Errorf(
-			"buildpack %s: must have either %s or an %s defined",
-			style.Symbol(bpd.Info.FullName()),
-			style.Symbol("stacks"),
-			style.Symbol("order"),
-		)
-	}
-
-	if len(bpd.Order) >= 1 && len(bpd.Stacks) >= 1 {
-		return errors.Errorf(
-			"buildpack %s: cannot have both %s and an %s defined",
-			style.Symbol(bpd.Info.FullName()),
-			style.Symbol("stacks"),
-			style.Symbol("order"),
-		)
+func isSupportedBuildpackAPIVersion(version string) bool {
+	for _, v := range SupportedBuildpackAPIVersions {
+		if v == version {
+			return true
+		}
 	}
-
-	return nil
+	return false
 }
@@ -0,0 +1,52 @@
+package dist
+
+import (
+	"sync"
+
+	"github.com/buildpacks/pack/internal/blob"
+)
+
+// BuildpackCache memoizes BuildpackFromRootBlob, keyed by blob checksum, so repeated calls over
+// identical blob content skip reparsing buildpack.toml and retranslating the distribution tar.
+// It is safe for concurrent use. BuildpackFromRootBlob itself stays pure; using the cache is
+// opt-in.
+type BuildpackCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedBuildpack
+}
+
+type cachedBuildpack struct {
+	once      sync.Once
+	buildpack Buildpack
+	err       error
+}
+
+// NewBuildpackCache returns an empty BuildpackCache.
+func NewBuildpackCache() *BuildpackCache {
+	return &BuildpackCache{entries: map[string]*cachedBuildpack{}}
+}
+
+// Get returns the Buildpack for b, computing it with BuildpackFromRootBlob(b, ops...) on the
+// first call for b's checksum and returning the cached result on every later call with the same
+// checksum. Concurrent calls for the same checksum block on one another rather than duplicating
+// the work.
+func (c *BuildpackCache) Get(b blob.Blob, ops ...BuildpackRootBlobOption) (Buildpack, error) {
+	checksum, err := b.Checksum()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[checksum]
+	if !ok {
+		entry = &cachedBuildpack{}
+		c.entries[checksum] = entry
+	}
+	c.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.buildpack, entry.err = BuildpackFromRootBlob(b, ops...)
+	})
+
+	return entry.buildpack, entry.err
+}
@@ -0,0 +1,109 @@
+package dist_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/archive"
+	"github.com/buildpacks/pack/internal/dist"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuildpackCache(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "BuildpackCache", testBuildpackCache, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBuildpackCache(t *testing.T, when spec.G, it spec.S) {
+	when("#Get", func() {
+		var newBlob func() *countingBlob
+
+		it.Before(func() {
+			newBlob = func() *countingBlob {
+				tarBuilder := archive.TarBuilder{}
+				tarBuilder.AddFile("buildpack.toml", 0700, archive.NormalizedDateTime, []byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`))
+				data, err := ioutil.ReadAll(tarBuilder.Reader())
+				h.AssertNil(t, err)
+				return &countingBlob{data: data}
+			}
+		})
+
+		it("parses a blob only once across repeated calls with the same checksum", func() {
+			b := newBlob()
+			cache := dist.NewBuildpackCache()
+
+			bp1, err := cache.Get(b)
+			h.AssertNil(t, err)
+
+			bp2, err := cache.Get(b)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, bp1.Descriptor().Info.FullName(), "bp.one@1.2.3")
+			h.AssertEq(t, bp2.Descriptor().Info.FullName(), "bp.one@1.2.3")
+
+			// BuildpackFromRootBlob opens the blob once to read buildpack.toml; a second call for the
+			// same checksum should reuse that result instead of opening the blob again.
+			if opens := atomic.LoadInt32(&b.opens); opens != 1 {
+				t.Fatalf("expected blob to be opened exactly once, got %d", opens)
+			}
+		})
+
+		it("is safe for concurrent use", func() {
+			b := newBlob()
+			cache := dist.NewBuildpackCache()
+
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := cache.Get(b)
+					h.AssertNil(t, err)
+				}()
+			}
+			wg.Wait()
+
+			if opens := atomic.LoadInt32(&b.opens); opens != 1 {
+				t.Fatalf("expected blob to be opened exactly once, got %d", opens)
+			}
+		})
+	})
+}
+
+// countingBlob is a blob.Blob backed by in-memory tar data, which counts how many times Open is
+// called.
+type countingBlob struct {
+	data  []byte
+	opens int32
+}
+
+func (b *countingBlob) Open() (io.ReadCloser, error) {
+	atomic.AddInt32(&b.opens, 1)
+	return ioutil.NopCloser(bytes.NewReader(b.data)), nil
+}
+
+func (b *countingBlob) Size() (int64, error) {
+	return int64(len(b.data)), nil
+}
+
+func (b *countingBlob) Checksum() (string, error) {
+	return "sha256:fixed-checksum-for-test", nil
+}
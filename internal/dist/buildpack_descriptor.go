@@ -5,22 +5,160 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/pkg/errors"
+
 	"github.com/buildpacks/pack/internal/api"
 	"github.com/buildpacks/pack/internal/stringset"
 	"github.com/buildpacks/pack/internal/style"
 )
 
 type BuildpackDescriptor struct {
-	API    *api.Version  `toml:"api"`
-	Info   BuildpackInfo `toml:"buildpack"`
-	Stacks []Stack       `toml:"stacks"`
-	Order  Order         `toml:"order"`
+	API     *api.Version  `toml:"api"`
+	Info    BuildpackInfo `toml:"buildpack"`
+	Stacks  []Stack       `toml:"stacks"`
+	Order   Order         `toml:"order"`
+	Targets []Target      `toml:"targets"`
+}
+
+// Target describes a platform (os, arch, and optional distros) that a buildpack supports. It is
+// a newer alternative to Stacks, introduced to support buildpack API versions that no longer key
+// compatibility off of a stack ID.
+type Target struct {
+	OS      string   `toml:"os" json:"os"`
+	Arch    string   `toml:"arch,omitempty" json:"arch,omitempty"`
+	Distros []string `toml:"distros,omitempty" json:"distros,omitempty"`
 }
 
 func (b *BuildpackDescriptor) EscapedID() string {
 	return strings.Replace(b.Info.ID, "/", "_", -1)
 }
 
+// Validate checks that b has an id, a version, a supported API version, exactly one of
+// stacks/targets/order defined, and -- for an order buildpack -- an id and version on every
+// group entry. Callers that construct a BuildpackDescriptor directly, without going through
+// BuildpackFromRootBlob or BuildpackFromOCILayoutBlob, should call this themselves. Only the
+// first problem found is returned; use ValidateAll to collect every problem at once.
+func (b *BuildpackDescriptor) Validate() error {
+	if errs := b.validate(); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// ValidateAll runs the same checks as Validate, but collects every problem found into a single
+// ValidationErrors instead of stopping at the first one. Meant for authoring tooling that wants
+// to report everything wrong with a buildpack.toml in one pass.
+func (b *BuildpackDescriptor) ValidateAll() error {
+	if errs := b.validate(); len(errs) > 0 {
+		return ValidationErrors(errs)
+	}
+	return nil
+}
+
+// ValidationErrors is every problem ValidateAll found with a BuildpackDescriptor.
+type ValidationErrors []error
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (b *BuildpackDescriptor) validate() []error {
+	var errs []error
+
+	if b.Info.ID == "" {
+		errs = append(errs, errors.Errorf("%s is required", style.Symbol("buildpack.id")))
+	}
+
+	if b.Info.Version == "" {
+		errs = append(errs, errors.Errorf("%s is required", style.Symbol("buildpack.version")))
+	}
+
+	if !isSupportedBuildpackAPIVersion(b.API.String()) {
+		errs = append(errs, errors.Errorf(
+			"buildpack API version %s is unsupported (supported: %s)",
+			style.Symbol(b.API.String()),
+			strings.Join(SupportedBuildpackAPIVersions, ", "),
+		))
+	}
+
+	definedCount := 0
+	for _, defined := range []bool{len(b.Order) > 0, len(b.Stacks) > 0, len(b.Targets) > 0} {
+		if defined {
+			definedCount++
+		}
+	}
+
+	if definedCount == 0 {
+		errs = append(errs, errors.Errorf(
+			"buildpack %s: must have either %s, %s, or an %s defined",
+			style.Symbol(b.Info.FullName()),
+			style.Symbol("stacks"),
+			style.Symbol("targets"),
+			style.Symbol("order"),
+		))
+	}
+
+	if definedCount > 1 {
+		errs = append(errs, errors.Errorf(
+			"buildpack %s: cannot have more than one of %s, %s, or an %s defined",
+			style.Symbol(b.Info.FullName()),
+			style.Symbol("stacks"),
+			style.Symbol("targets"),
+			style.Symbol("order"),
+		))
+	}
+
+	for _, target := range b.Targets {
+		if target.OS == "" {
+			errs = append(errs, errors.Errorf(
+				"buildpack %s: each %s must have an %s defined",
+				style.Symbol(b.Info.FullName()),
+				style.Symbol("target"),
+				style.Symbol("os"),
+			))
+		}
+	}
+
+	for i, stack := range b.Stacks {
+		if stack.ID == "" {
+			errs = append(errs, errors.Errorf(
+				"buildpack %s: stacks[%d] is missing %s",
+				style.Symbol(b.Info.FullName()),
+				i,
+				style.Symbol("id"),
+			))
+		}
+	}
+
+	for i, entry := range b.Order {
+		for j, ref := range entry.Group {
+			if ref.ID == "" {
+				errs = append(errs, errors.Errorf(
+					"buildpack %s: order[%d].group[%d] is missing %s",
+					style.Symbol(b.Info.FullName()),
+					i, j,
+					style.Symbol("id"),
+				))
+			}
+
+			if ref.Version == "" {
+				errs = append(errs, errors.Errorf(
+					"buildpack %s: order[%d].group[%d] is missing %s",
+					style.Symbol(b.Info.FullName()),
+					i, j,
+					style.Symbol("version"),
+				))
+			}
+		}
+	}
+
+	return errs
+}
+
 func (b *BuildpackDescriptor) EnsureStackSupport(stackID string, providedMixins []string, validateRunStageMixins bool) error {
 	if len(b.Stacks) == 0 {
 		return nil // Order buildpack, no validation required
@@ -7,6 +7,7 @@ import (
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
+	"github.com/buildpacks/pack/internal/api"
 	h "github.com/buildpacks/pack/testhelpers"
 )
 
@@ -17,6 +18,90 @@ func TestBuildpackDescriptor(t *testing.T) {
 }
 
 func testBuildpackDescriptor(t *testing.T, when spec.G, it spec.S) {
+	when("#Validate", func() {
+		var bp BuildpackDescriptor
+
+		it.Before(func() {
+			bp = BuildpackDescriptor{
+				API:  api.MustParse(AssumedBuildpackAPIVersion),
+				Info: BuildpackInfo{ID: "some.buildpack.id", Version: "some.buildpack.version"},
+				Stacks: []Stack{{
+					ID: "some.stack.id",
+				}},
+			}
+		})
+
+		it("accepts a valid descriptor", func() {
+			h.AssertNil(t, bp.Validate())
+		})
+
+		it("requires an id", func() {
+			bp.Info.ID = ""
+			h.AssertError(t, bp.Validate(), "'buildpack.id' is required")
+		})
+
+		it("requires a version", func() {
+			bp.Info.Version = ""
+			h.AssertError(t, bp.Validate(), "'buildpack.version' is required")
+		})
+
+		it("requires a supported API version", func() {
+			bp.API = api.MustParse("0.99")
+			h.AssertError(t, bp.Validate(), "buildpack API version '0.99' is unsupported")
+		})
+
+		it("requires one of stacks, targets, or order", func() {
+			bp.Stacks = nil
+			h.AssertError(t, bp.Validate(), "must have either 'stacks', 'targets', or an 'order' defined")
+		})
+
+		it("requires an id on every stack", func() {
+			bp.Stacks = []Stack{{ID: "some.stack.id"}, {ID: ""}}
+			h.AssertError(t, bp.Validate(), "stacks[1] is missing 'id'")
+		})
+
+		it("rejects more than one of stacks, targets, or order", func() {
+			bp.Targets = []Target{{OS: "linux"}}
+			h.AssertError(t, bp.Validate(), "cannot have more than one of 'stacks', 'targets', or an 'order' defined")
+		})
+
+		it("requires an os on every target", func() {
+			bp.Stacks = nil
+			bp.Targets = []Target{{Arch: "amd64"}}
+			h.AssertError(t, bp.Validate(), "each 'target' must have an 'os' defined")
+		})
+
+		it("requires an id and version on every order group entry", func() {
+			bp.Stacks = nil
+			bp.Order = Order{{Group: []BuildpackRef{{BuildpackInfo: BuildpackInfo{Version: "some.version"}}}}}
+			h.AssertError(t, bp.Validate(), "order[0].group[0] is missing 'id'")
+
+			bp.Order = Order{{Group: []BuildpackRef{{BuildpackInfo: BuildpackInfo{ID: "some.id"}}}}}
+			h.AssertError(t, bp.Validate(), "order[0].group[0] is missing 'version'")
+		})
+	})
+
+	when("#ValidateAll", func() {
+		it("collects every problem instead of stopping at the first", func() {
+			bp := BuildpackDescriptor{API: api.MustParse(AssumedBuildpackAPIVersion)}
+
+			err := bp.ValidateAll()
+			h.AssertError(t, err, "'buildpack.id' is required")
+			h.AssertError(t, err, "'buildpack.version' is required")
+			h.AssertError(t, err, "must have either 'stacks', 'targets', or an 'order' defined")
+		})
+
+		it("returns nil for a valid descriptor", func() {
+			bp := BuildpackDescriptor{
+				API:    api.MustParse(AssumedBuildpackAPIVersion),
+				Info:   BuildpackInfo{ID: "some.buildpack.id", Version: "some.buildpack.version"},
+				Stacks: []Stack{{ID: "some.stack.id"}},
+			}
+
+			h.AssertNil(t, bp.ValidateAll())
+		})
+	})
+
 	when("#EnsureStackSupport", func() {
 		when("not validating against run image mixins", func() {
 			it("ignores run-only mixins", func() {
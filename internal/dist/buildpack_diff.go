@@ -0,0 +1,130 @@
+package dist
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/buildpacks/pack/internal/stringset"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// ChangeKind labels the kind of difference DiffDescriptors found between two BuildpackDescriptors.
+type ChangeKind string
+
+const (
+	ChangeAPIVersion   ChangeKind = "api-version-changed"
+	ChangeVersion      ChangeKind = "version-changed"
+	ChangeKindChanged  ChangeKind = "kind-changed"
+	ChangeStackAdded   ChangeKind = "stack-added"
+	ChangeStackRemoved ChangeKind = "stack-removed"
+	ChangeOrderAdded   ChangeKind = "order-group-added"
+	ChangeOrderRemoved ChangeKind = "order-group-removed"
+)
+
+// Change is a single difference found by DiffDescriptors.
+type Change struct {
+	Kind    ChangeKind
+	Message string
+}
+
+// DiffDescriptors compares a and b -- typically a currently-published buildpack descriptor and a
+// candidate meant to replace it -- and reports every difference a promotion pipeline would care
+// about: API and version changes, added/removed stacks, and added/removed order groups. Stacks
+// and order are compared independently of one another: a buildpack switching from a stacks
+// buildpack to an order buildpack (or back) is reported as a single ChangeKindChanged rather than
+// a confusing pile of stack/order adds and removes, since the two aren't comparable.
+func DiffDescriptors(a, b BuildpackDescriptor) []Change {
+	var changes []Change
+
+	if a.API.String() != b.API.String() {
+		changes = append(changes, Change{
+			Kind:    ChangeAPIVersion,
+			Message: fmt.Sprintf("api version changed from %s to %s", style.Symbol(a.API.String()), style.Symbol(b.API.String())),
+		})
+	}
+
+	if a.Info.Version != b.Info.Version {
+		changes = append(changes, Change{
+			Kind:    ChangeVersion,
+			Message: fmt.Sprintf("version changed from %s to %s", style.Symbol(a.Info.Version), style.Symbol(b.Info.Version)),
+		})
+	}
+
+	aIsOrder, bIsOrder := len(a.Order) > 0, len(b.Order) > 0
+	if aIsOrder != bIsOrder {
+		changes = append(changes, Change{
+			Kind:    ChangeKindChanged,
+			Message: fmt.Sprintf("changed from a %s buildpack to a %s buildpack", descriptorKind(aIsOrder), descriptorKind(bIsOrder)),
+		})
+		return changes
+	}
+
+	if aIsOrder {
+		changes = append(changes, diffOrder(a.Order, b.Order)...)
+	} else {
+		changes = append(changes, diffStacks(a.Stacks, b.Stacks)...)
+	}
+
+	return changes
+}
+
+func descriptorKind(isOrder bool) string {
+	if isOrder {
+		return "order"
+	}
+	return "stacks"
+}
+
+func diffStacks(a, b []Stack) []Change {
+	removed, added, _ := stringset.Compare(stackIDs(a), stackIDs(b))
+	sort.Strings(removed)
+	sort.Strings(added)
+
+	var changes []Change
+	for _, id := range removed {
+		changes = append(changes, Change{Kind: ChangeStackRemoved, Message: fmt.Sprintf("stack removed: %s", style.Symbol(id))})
+	}
+	for _, id := range added {
+		changes = append(changes, Change{Kind: ChangeStackAdded, Message: fmt.Sprintf("stack added: %s", style.Symbol(id))})
+	}
+	return changes
+}
+
+func stackIDs(stacks []Stack) []string {
+	ids := make([]string, len(stacks))
+	for i, s := range stacks {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+func diffOrder(a, b Order) []Change {
+	removed, added, _ := stringset.Compare(orderGroupKeys(a), orderGroupKeys(b))
+	sort.Strings(removed)
+	sort.Strings(added)
+
+	var changes []Change
+	for _, group := range removed {
+		changes = append(changes, Change{Kind: ChangeOrderRemoved, Message: fmt.Sprintf("order group removed: %s", style.Symbol(group))})
+	}
+	for _, group := range added {
+		changes = append(changes, Change{Kind: ChangeOrderAdded, Message: fmt.Sprintf("order group added: %s", style.Symbol(group))})
+	}
+	return changes
+}
+
+// orderGroupKeys renders each order entry's group as a deterministic, order-independent key, so
+// two groups with the same members in a different order compare as equal.
+func orderGroupKeys(order Order) []string {
+	keys := make([]string, len(order))
+	for i, entry := range order {
+		names := make([]string, len(entry.Group))
+		for j, ref := range entry.Group {
+			names[j] = ref.FullName()
+		}
+		sort.Strings(names)
+		keys[i] = strings.Join(names, "+")
+	}
+	return keys
+}
@@ -0,0 +1,130 @@
+package dist_test
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/api"
+	"github.com/buildpacks/pack/internal/dist"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuildpackDiff(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "BuildpackDiff", testBuildpackDiff, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBuildpackDiff(t *testing.T, when spec.G, it spec.S) {
+	when("#DiffDescriptors", func() {
+		var base dist.BuildpackDescriptor
+
+		it.Before(func() {
+			base = dist.BuildpackDescriptor{
+				API:    api.MustParse("0.3"),
+				Info:   dist.BuildpackInfo{ID: "some.buildpack.id", Version: "1.0.0"},
+				Stacks: []dist.Stack{{ID: "stack.one"}, {ID: "stack.two"}},
+			}
+		})
+
+		it("returns no changes for identical descriptors", func() {
+			h.AssertEq(t, dist.DiffDescriptors(base, base), []dist.Change(nil))
+		})
+
+		it("reports an api version change", func() {
+			other := base
+			other.API = api.MustParse("0.4")
+
+			changes := dist.DiffDescriptors(base, other)
+			h.AssertEq(t, len(changes), 1)
+			h.AssertEq(t, changes[0].Kind, dist.ChangeAPIVersion)
+		})
+
+		it("reports a version change", func() {
+			other := base
+			other.Info.Version = "2.0.0"
+
+			changes := dist.DiffDescriptors(base, other)
+			h.AssertEq(t, len(changes), 1)
+			h.AssertEq(t, changes[0].Kind, dist.ChangeVersion)
+		})
+
+		it("reports added and removed stacks", func() {
+			other := base
+			other.Stacks = []dist.Stack{{ID: "stack.two"}, {ID: "stack.three"}}
+
+			changes := dist.DiffDescriptors(base, other)
+			h.AssertEq(t, len(changes), 2)
+
+			var kinds []string
+			for _, c := range changes {
+				kinds = append(kinds, string(c.Kind))
+			}
+			h.AssertSliceContains(t, kinds, string(dist.ChangeStackRemoved), string(dist.ChangeStackAdded))
+		})
+
+		it("reports added and removed order groups", func() {
+			a := dist.BuildpackDescriptor{
+				API:  api.MustParse("0.3"),
+				Info: dist.BuildpackInfo{ID: "some.buildpack.id", Version: "1.0.0"},
+				Order: dist.Order{{Group: []dist.BuildpackRef{
+					{BuildpackInfo: dist.BuildpackInfo{ID: "dep.one", Version: "1.0.0"}},
+				}}},
+			}
+			b := dist.BuildpackDescriptor{
+				API:  api.MustParse("0.3"),
+				Info: dist.BuildpackInfo{ID: "some.buildpack.id", Version: "1.0.0"},
+				Order: dist.Order{{Group: []dist.BuildpackRef{
+					{BuildpackInfo: dist.BuildpackInfo{ID: "dep.two", Version: "1.0.0"}},
+				}}},
+			}
+
+			changes := dist.DiffDescriptors(a, b)
+			h.AssertEq(t, len(changes), 2)
+
+			var kinds []string
+			for _, c := range changes {
+				kinds = append(kinds, string(c.Kind))
+			}
+			h.AssertSliceContains(t, kinds, string(dist.ChangeOrderRemoved), string(dist.ChangeOrderAdded))
+		})
+
+		it("treats order groups with the same members in a different order as unchanged", func() {
+			a := dist.BuildpackDescriptor{
+				API:  api.MustParse("0.3"),
+				Info: dist.BuildpackInfo{ID: "some.buildpack.id", Version: "1.0.0"},
+				Order: dist.Order{{Group: []dist.BuildpackRef{
+					{BuildpackInfo: dist.BuildpackInfo{ID: "dep.one", Version: "1.0.0"}},
+					{BuildpackInfo: dist.BuildpackInfo{ID: "dep.two", Version: "1.0.0"}},
+				}}},
+			}
+			b := dist.BuildpackDescriptor{
+				API:  api.MustParse("0.3"),
+				Info: dist.BuildpackInfo{ID: "some.buildpack.id", Version: "1.0.0"},
+				Order: dist.Order{{Group: []dist.BuildpackRef{
+					{BuildpackInfo: dist.BuildpackInfo{ID: "dep.two", Version: "1.0.0"}},
+					{BuildpackInfo: dist.BuildpackInfo{ID: "dep.one", Version: "1.0.0"}},
+				}}},
+			}
+
+			h.AssertEq(t, dist.DiffDescriptors(a, b), []dist.Change(nil))
+		})
+
+		it("reports a single change when a buildpack switches between stacks and order", func() {
+			other := dist.BuildpackDescriptor{
+				API:  api.MustParse("0.3"),
+				Info: dist.BuildpackInfo{ID: "some.buildpack.id", Version: "1.0.0"},
+				Order: dist.Order{{Group: []dist.BuildpackRef{
+					{BuildpackInfo: dist.BuildpackInfo{ID: "dep.one", Version: "1.0.0"}},
+				}}},
+			}
+
+			changes := dist.DiffDescriptors(base, other)
+			h.AssertEq(t, len(changes), 1)
+			h.AssertEq(t, changes[0].Kind, dist.ChangeKindChanged)
+		})
+	})
+}
@@ -0,0 +1,67 @@
+package dist
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// FlattenBuildpacks resolves the transitive closure of leaf buildpacks reachable from root's
+// order groups against the buildpacks in available, returning each leaf exactly once. A "leaf"
+// buildpack is one with no order of its own -- i.e. one that actually implements /bin/detect and
+// /bin/build, as opposed to a meta-buildpack that only groups other buildpacks together. Each
+// reference is resolved by id and version against available; a reference to a buildpack that
+// isn't present, or a cycle in the order groups, is reported as an error rather than recursing
+// forever.
+func FlattenBuildpacks(available []Buildpack, root BuildpackInfo) ([]Buildpack, error) {
+	byFullName := map[string]Buildpack{}
+	for _, bp := range available {
+		byFullName[bp.Descriptor().Info.FullName()] = bp
+	}
+
+	var leaves []Buildpack
+	resolved := map[string]bool{}
+	resolving := map[string]bool{}
+
+	var resolve func(ref BuildpackInfo) error
+	resolve = func(ref BuildpackInfo) error {
+		fullName := ref.FullName()
+		if resolved[fullName] {
+			return nil
+		}
+		if resolving[fullName] {
+			return errors.Errorf("cycle detected while resolving buildpack %s", style.Symbol(fullName))
+		}
+
+		bp, ok := byFullName[fullName]
+		if !ok {
+			return errors.Errorf("buildpack %s not found among provided buildpacks", style.Symbol(fullName))
+		}
+
+		bpd := bp.Descriptor()
+		if len(bpd.Order) == 0 {
+			leaves = append(leaves, bp)
+			resolved[fullName] = true
+			return nil
+		}
+
+		resolving[fullName] = true
+		for _, entry := range bpd.Order {
+			for _, groupRef := range entry.Group {
+				if err := resolve(groupRef.BuildpackInfo); err != nil {
+					return err
+				}
+			}
+		}
+		delete(resolving, fullName)
+		resolved[fullName] = true
+
+		return nil
+	}
+
+	if err := resolve(root); err != nil {
+		return nil, err
+	}
+
+	return leaves, nil
+}
@@ -0,0 +1,105 @@
+package dist_test
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/api"
+	"github.com/buildpacks/pack/internal/dist"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuildpackFlatten(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "BuildpackFlatten", testBuildpackFlatten, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func leafBuildpack(id, version string) dist.Buildpack {
+	return dist.BuildpackFromBlob(dist.BuildpackDescriptor{
+		API:    api.MustParse("0.3"),
+		Info:   dist.BuildpackInfo{ID: id, Version: version},
+		Stacks: []dist.Stack{{ID: "some.stack.id"}},
+	}, nil)
+}
+
+func metaBuildpack(id, version string, group ...dist.BuildpackRef) dist.Buildpack {
+	return dist.BuildpackFromBlob(dist.BuildpackDescriptor{
+		API:   api.MustParse("0.3"),
+		Info:  dist.BuildpackInfo{ID: id, Version: version},
+		Order: dist.Order{{Group: group}},
+	}, nil)
+}
+
+func ref(id, version string) dist.BuildpackRef {
+	return dist.BuildpackRef{BuildpackInfo: dist.BuildpackInfo{ID: id, Version: version}}
+}
+
+func testBuildpackFlatten(t *testing.T, when spec.G, it spec.S) {
+	when("#FlattenBuildpacks", func() {
+		it("returns the root itself when it has no order", func() {
+			root := leafBuildpack("bp.leaf", "1.0.0")
+
+			leaves, err := dist.FlattenBuildpacks([]dist.Buildpack{root}, root.Descriptor().Info)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(leaves), 1)
+			h.AssertEq(t, leaves[0].Descriptor().Info.FullName(), "bp.leaf@1.0.0")
+		})
+
+		it("flattens nested meta-buildpacks into their leaf buildpacks", func() {
+			leafA := leafBuildpack("bp.a", "1.0.0")
+			leafB := leafBuildpack("bp.b", "1.0.0")
+			inner := metaBuildpack("bp.inner", "1.0.0", ref("bp.a", "1.0.0"), ref("bp.b", "1.0.0"))
+			leafC := leafBuildpack("bp.c", "1.0.0")
+			root := metaBuildpack("bp.root", "1.0.0", ref("bp.inner", "1.0.0"), ref("bp.c", "1.0.0"))
+
+			available := []dist.Buildpack{leafA, leafB, inner, leafC, root}
+
+			leaves, err := dist.FlattenBuildpacks(available, root.Descriptor().Info)
+			h.AssertNil(t, err)
+
+			var names []string
+			for _, bp := range leaves {
+				names = append(names, bp.Descriptor().Info.FullName())
+			}
+			h.AssertSliceContains(t, names, "bp.a@1.0.0", "bp.b@1.0.0", "bp.c@1.0.0")
+		})
+
+		it("returns each leaf once even if referenced by multiple meta-buildpacks", func() {
+			shared := leafBuildpack("bp.shared", "1.0.0")
+			left := metaBuildpack("bp.left", "1.0.0", ref("bp.shared", "1.0.0"))
+			right := metaBuildpack("bp.right", "1.0.0", ref("bp.shared", "1.0.0"))
+			root := metaBuildpack("bp.root", "1.0.0", ref("bp.left", "1.0.0"), ref("bp.right", "1.0.0"))
+
+			available := []dist.Buildpack{shared, left, right, root}
+
+			leaves, err := dist.FlattenBuildpacks(available, root.Descriptor().Info)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(leaves), 1)
+			h.AssertEq(t, leaves[0].Descriptor().Info.FullName(), "bp.shared@1.0.0")
+		})
+
+		it("returns an error when a referenced buildpack is missing", func() {
+			root := metaBuildpack("bp.root", "1.0.0", ref("bp.missing", "1.0.0"))
+
+			_, err := dist.FlattenBuildpacks([]dist.Buildpack{root}, root.Descriptor().Info)
+			h.AssertError(t, err, "buildpack 'bp.missing@1.0.0' not found among provided buildpacks")
+		})
+
+		it("returns an error when the root itself is missing", func() {
+			_, err := dist.FlattenBuildpacks(nil, dist.BuildpackInfo{ID: "bp.root", Version: "1.0.0"})
+			h.AssertError(t, err, "buildpack 'bp.root@1.0.0' not found among provided buildpacks")
+		})
+
+		it("returns an error when the order groups form a cycle", func() {
+			a := metaBuildpack("bp.a", "1.0.0", ref("bp.b", "1.0.0"))
+			b := metaBuildpack("bp.b", "1.0.0", ref("bp.a", "1.0.0"))
+
+			_, err := dist.FlattenBuildpacks([]dist.Buildpack{a, b}, a.Descriptor().Info)
+			h.AssertError(t, err, "cycle detected while resolving buildpack 'bp.a@1.0.0'")
+		})
+	})
+}
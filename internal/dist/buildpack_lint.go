@@ -0,0 +1,54 @@
+package dist
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+)
+
+// Warning describes a non-fatal problem found by LintBuildpackTOML. Unlike the errors returned by
+// Validate and ValidateAll, a Warning doesn't fail the parse -- it's meant to be surfaced to a
+// buildpack author (e.g. by a "pack buildpack lint" command) without blocking a build.
+type Warning struct {
+	Key     string
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Key, w.Message)
+}
+
+// LintBuildpackTOML parses data as a buildpack.toml and reports warnings for unknown keys,
+// reliance on deprecated defaults, and suspicious values -- such as a non-semver version --
+// without failing the parse the way BuildpackFromRootBlob does. It reuses BuildpackDescriptor to
+// recognize known keys, so any key BuildpackDescriptor doesn't map is reported as unknown.
+func LintBuildpackTOML(data []byte) ([]Warning, error) {
+	bpd := BuildpackDescriptor{}
+	md, err := toml.Decode(string(data), &bpd)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding buildpack.toml")
+	}
+
+	var warnings []Warning
+
+	for _, key := range md.Undecoded() {
+		warnings = append(warnings, Warning{Key: key.String(), Message: "unknown key"})
+	}
+
+	if !md.IsDefined("api") {
+		warnings = append(warnings, Warning{
+			Key:     "api",
+			Message: fmt.Sprintf("missing; will be assumed to be %s, a default that will be removed in a future release", AssumedBuildpackAPIVersion),
+		})
+	}
+
+	if bpd.Info.Version != "" {
+		if _, err := semver.NewVersion(bpd.Info.Version); err != nil {
+			warnings = append(warnings, Warning{Key: "buildpack.version", Message: "is not a valid semantic version"})
+		}
+	}
+
+	return warnings, nil
+}
@@ -0,0 +1,108 @@
+package dist_test
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/dist"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuildpackLint(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "BuildpackLint", testBuildpackLint, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBuildpackLint(t *testing.T, when spec.G, it spec.S) {
+	when("#LintBuildpackTOML", func() {
+		it("returns no warnings for a clean buildpack.toml", func() {
+			warnings, err := dist.LintBuildpackTOML([]byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`))
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(warnings), 0)
+		})
+
+		it("flags unknown keys", func() {
+			warnings, err := dist.LintBuildpackTOML([]byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+flavor = "vanilla"
+
+[[stacks]]
+id = "some.stack.id"
+`))
+			h.AssertNil(t, err)
+
+			found := false
+			for _, w := range warnings {
+				if w.Key == "buildpack.flavor" {
+					found = true
+					h.AssertEq(t, w.Message, "unknown key")
+				}
+			}
+			h.AssertTrue(t, found)
+		})
+
+		it("flags a missing api field as relying on a deprecated default", func() {
+			warnings, err := dist.LintBuildpackTOML([]byte(`
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`))
+			h.AssertNil(t, err)
+
+			found := false
+			for _, w := range warnings {
+				if w.Key == "api" {
+					found = true
+				}
+			}
+			h.AssertTrue(t, found)
+		})
+
+		it("flags a non-semver version", func() {
+			warnings, err := dist.LintBuildpackTOML([]byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "not-a-version"
+
+[[stacks]]
+id = "some.stack.id"
+`))
+			h.AssertNil(t, err)
+
+			found := false
+			for _, w := range warnings {
+				if w.Key == "buildpack.version" {
+					found = true
+				}
+			}
+			h.AssertTrue(t, found)
+		})
+
+		it("returns an error for malformed toml", func() {
+			_, err := dist.LintBuildpackTOML([]byte(`not valid toml =`))
+			h.AssertError(t, err, "decoding buildpack.toml")
+		})
+	})
+}
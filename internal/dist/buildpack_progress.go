@@ -0,0 +1,32 @@
+package dist
+
+import "io"
+
+// OpenWithProgress opens bp's distribution tar, like Open, but calls progress with the
+// cumulative number of bytes read every time a Read off the returned reader returns data. It's
+// meant for callers -- like the CLI rendering an upload progress bar -- that want to observe how
+// far a large buildpack's tar has been consumed without the dist package knowing anything about
+// how that progress is displayed.
+func OpenWithProgress(bp Buildpack, progress func(bytesRead int64)) (io.ReadCloser, error) {
+	rc, err := bp.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	return &progressReadCloser{ReadCloser: rc, progress: progress}, nil
+}
+
+type progressReadCloser struct {
+	io.ReadCloser
+	total    int64
+	progress func(int64)
+}
+
+func (p *progressReadCloser) Read(b []byte) (int, error) {
+	n, err := p.ReadCloser.Read(b)
+	if n > 0 {
+		p.total += int64(n)
+		p.progress(p.total)
+	}
+	return n, err
+}
@@ -0,0 +1,88 @@
+package dist_test
+
+import (
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/dist"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuildpackProgress(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "BuildpackProgress", testBuildpackProgress, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBuildpackProgress(t *testing.T, when spec.G, it spec.S) {
+	when("#OpenWithProgress", func() {
+		it("reports cumulative bytes read as the reader is consumed", func() {
+			bp := &progressFakeBuildpack{data: []byte("0123456789")}
+
+			var reported []int64
+			rc, err := dist.OpenWithProgress(bp, func(bytesRead int64) {
+				reported = append(reported, bytesRead)
+			})
+			h.AssertNil(t, err)
+			defer rc.Close()
+
+			buf := make([]byte, 4)
+			for {
+				_, err := rc.Read(buf)
+				if err == io.EOF {
+					break
+				}
+				h.AssertNil(t, err)
+			}
+
+			h.AssertEq(t, reported, []int64{4, 8, 10})
+		})
+
+		it("surfaces an error from opening the underlying buildpack", func() {
+			bp := &progressFakeBuildpack{openErr: errOpenFailed}
+
+			_, err := dist.OpenWithProgress(bp, func(int64) {})
+			h.AssertError(t, err, errOpenFailed.Error())
+		})
+	})
+}
+
+var errOpenFailed = &buildpackOpenError{}
+
+type buildpackOpenError struct{}
+
+func (e *buildpackOpenError) Error() string { return "failed to open buildpack" }
+
+type progressFakeBuildpack struct {
+	data    []byte
+	openErr error
+}
+
+func (b *progressFakeBuildpack) Open() (io.ReadCloser, error) {
+	if b.openErr != nil {
+		return nil, b.openErr
+	}
+	return ioutil.NopCloser(&byteReader{data: b.data}), nil
+}
+
+func (b *progressFakeBuildpack) Descriptor() dist.BuildpackDescriptor {
+	return dist.BuildpackDescriptor{}
+}
+
+type byteReader struct {
+	data []byte
+}
+
+func (r *byteReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
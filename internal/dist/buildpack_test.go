@@ -1,6 +1,10 @@
 package dist_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
 	"errors"
 	"io"
 	"io/ioutil"
@@ -10,11 +14,14 @@ import (
 	"time"
 
 	"github.com/heroku/color"
+	digest "github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
 	"github.com/buildpacks/pack/internal/archive"
 	"github.com/buildpacks/pack/internal/dist"
+	ilogging "github.com/buildpacks/pack/internal/logging"
 	h "github.com/buildpacks/pack/testhelpers"
 )
 
@@ -59,6 +66,7 @@ api = "0.3"
 id = "bp.one"
 version = "1.2.3"
 homepage = "http://geocities.com/cool-bp"
+description = "a cool buildpack"
 
 [[stacks]]
 id = "some.stack.id"
@@ -72,9 +80,317 @@ id = "some.stack.id"
 			h.AssertEq(t, bp.Descriptor().Info.ID, "bp.one")
 			h.AssertEq(t, bp.Descriptor().Info.Version, "1.2.3")
 			h.AssertEq(t, bp.Descriptor().Info.Homepage, "http://geocities.com/cool-bp")
+			h.AssertEq(t, bp.Descriptor().Info.Description, "a cool buildpack")
 			h.AssertEq(t, bp.Descriptor().Stacks[0].ID, "some.stack.id")
 		})
 
+		it("trims whitespace from stack ids", func() {
+			bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "  some.stack.id  "
+`))
+					return tarBuilder.Reader()
+				},
+			})
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp.Descriptor().Stacks[0].ID, "some.stack.id")
+		})
+
+		it("returns an error naming the offending stack when a stack id is empty", func() {
+			_, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+
+[[stacks]]
+id = "   "
+`))
+					return tarBuilder.Reader()
+				},
+			})
+			h.AssertError(t, err, "stacks[1] is missing 'id'")
+		})
+
+		it("parses the descriptor file from a gzip-compressed blob", func() {
+			bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`))
+
+					pr, pw := io.Pipe()
+					go func() {
+						gzw := gzip.NewWriter(pw)
+						_, err := tarBuilder.WriteTo(gzw)
+						gzw.Close()
+						pw.CloseWithError(err)
+					}()
+					return pr
+				},
+			})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, bp.Descriptor().Info.ID, "bp.one")
+			h.AssertEq(t, bp.Descriptor().Info.Version, "1.2.3")
+		})
+
+		it("parses stack mixins", func() {
+			bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+mixins = ["mixinX", "build:mixinY", "run:mixinZ"]
+`))
+					return tarBuilder.Reader()
+				},
+			})
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp.Descriptor().Stacks[0].Mixins, []string{"mixinX", "build:mixinY", "run:mixinZ"})
+		})
+
+		it("parses the targets table", func() {
+			bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[targets]]
+os = "linux"
+arch = "amd64"
+distros = ["ubuntu:18.04"]
+`))
+					return tarBuilder.Reader()
+				},
+			})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(bp.Descriptor().Targets), 1)
+			h.AssertEq(t, bp.Descriptor().Targets[0].OS, "linux")
+			h.AssertEq(t, bp.Descriptor().Targets[0].Arch, "amd64")
+			h.AssertEq(t, bp.Descriptor().Targets[0].Distros, []string{"ubuntu:18.04"})
+		})
+
+		it("warns when api is missing from buildpack.toml", func() {
+			var out bytes.Buffer
+			logger := ilogging.NewLogWithWriters(&out, &out)
+
+			bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`))
+					return tarBuilder.Reader()
+				},
+			}, dist.WithLogger(logger))
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp.Descriptor().API.String(), "0.1")
+
+			h.AssertContains(t, out.String(), "Buildpack 'bp.one@1.2.3' is missing 'api'")
+		})
+
+		it("returns an error if the buildpack API version is unsupported", func() {
+			_, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+api = "0.99"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`))
+					return tarBuilder.Reader()
+				},
+			})
+			h.AssertError(t, err, "buildpack API version '0.99' is unsupported (supported: 0.1, 0.2, 0.3)")
+		})
+
+		it("requires an os for each target", func() {
+			_, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[targets]]
+arch = "amd64"
+`))
+					return tarBuilder.Reader()
+				},
+			})
+			h.AssertError(t, err, "each 'target' must have an 'os' defined")
+		})
+
+		it("parses the order table", func() {
+			bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[order]]
+[[order.group]]
+id = "bp.two"
+version = "2.3.4"
+
+[[order.group]]
+id = "bp.three"
+version = "3.4.5"
+`))
+					return tarBuilder.Reader()
+				},
+			})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(bp.Descriptor().Order), 1)
+			group := bp.Descriptor().Order[0].Group
+			h.AssertEq(t, len(group), 2)
+			h.AssertEq(t, group[0].ID, "bp.two")
+			h.AssertEq(t, group[0].Version, "2.3.4")
+			h.AssertEq(t, group[1].ID, "bp.three")
+			h.AssertEq(t, group[1].Version, "3.4.5")
+		})
+
+		it("parses the optional flag on order group entries", func() {
+			bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[order]]
+[[order.group]]
+id = "bp.two"
+version = "2.3.4"
+
+[[order.group]]
+id = "bp.three"
+version = "3.4.5"
+optional = true
+`))
+					return tarBuilder.Reader()
+				},
+			})
+			h.AssertNil(t, err)
+
+			group := bp.Descriptor().Order[0].Group
+			h.AssertEq(t, group[0].Optional, false)
+			h.AssertEq(t, group[1].Optional, true)
+		})
+
+		it("requires an id for each order group entry", func() {
+			_, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[order]]
+[[order.group]]
+version = "2.3.4"
+`))
+					return tarBuilder.Reader()
+				},
+			})
+			h.AssertError(t, err, "order[0].group[0] is missing 'id'")
+		})
+
+		it("requires a version for each order group entry", func() {
+			_, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[order]]
+[[order.group]]
+id = "bp.two"
+`))
+					return tarBuilder.Reader()
+				},
+			})
+			h.AssertError(t, err, "order[0].group[0] is missing 'version'")
+		})
+
+		it("parses the descriptor file from an OCI layout blob", func() {
+			bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					return buildOCILayoutBlob(t, []byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`))
+				},
+			})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, bp.Descriptor().API.String(), "0.3")
+			h.AssertEq(t, bp.Descriptor().Info.ID, "bp.one")
+			h.AssertEq(t, bp.Descriptor().Info.Version, "1.2.3")
+		})
+
 		it("translates blob to distribution format", func() {
 			bp, err := dist.BuildpackFromRootBlob(&readerBlob{
 				openFn: func() io.ReadCloser {
@@ -89,52 +405,388 @@ version = "1.2.3"
 [[stacks]]
 id = "some.stack.id"
 `))
-
-					tarBuilder.AddDir("bin", 0700, time.Now())
-					tarBuilder.AddFile("bin/detect", 0700, time.Now(), []byte("detect-contents"))
-					tarBuilder.AddFile("bin/build", 0700, time.Now(), []byte("build-contents"))
+
+					tarBuilder.AddDir("bin", 0700, time.Now())
+					tarBuilder.AddFile("bin/detect", 0700, time.Now(), []byte("detect-contents"))
+					tarBuilder.AddFile("bin/build", 0700, time.Now(), []byte("build-contents"))
+					return tarBuilder.Reader()
+				},
+			})
+			h.AssertNil(t, err)
+
+			tarPath := writeBlobToFile(bp)
+			defer os.Remove(tarPath)
+
+			h.AssertOnTarEntry(t, tarPath,
+				"/cnb/buildpacks/bp.one",
+				h.IsDirectory(),
+				h.HasFileMode(0755),
+				h.HasModTime(archive.NormalizedDateTime),
+			)
+
+			h.AssertOnTarEntry(t, tarPath,
+				"/cnb/buildpacks/bp.one/1.2.3",
+				h.IsDirectory(),
+				h.HasFileMode(0755),
+				h.HasModTime(archive.NormalizedDateTime),
+			)
+
+			h.AssertOnTarEntry(t, tarPath,
+				"/cnb/buildpacks/bp.one/1.2.3/bin",
+				h.IsDirectory(),
+				h.HasFileMode(0755),
+				h.HasModTime(archive.NormalizedDateTime),
+			)
+
+			h.AssertOnTarEntry(t, tarPath,
+				"/cnb/buildpacks/bp.one/1.2.3/bin/detect",
+				h.HasFileMode(0755),
+				h.HasModTime(archive.NormalizedDateTime),
+				h.ContentEquals("detect-contents"),
+			)
+
+			h.AssertOnTarEntry(t, tarPath,
+				"/cnb/buildpacks/bp.one/1.2.3/bin/build",
+				h.HasFileMode(0755),
+				h.HasModTime(archive.NormalizedDateTime),
+				h.ContentEquals("build-contents"),
+			)
+		})
+
+		it("preserves multiple versions", func() {
+			bps, err := dist.BuildpackFromOCILayoutBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					return buildOCILayoutBlobs(t, [][]byte{
+						[]byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`),
+						[]byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "4.5.6"
+
+[[stacks]]
+id = "some.stack.id"
+`),
+					})
+				},
+			})
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(bps), 2)
+
+			versions := map[string]bool{}
+			for _, bp := range bps {
+				versions[bp.Descriptor().Info.Version] = true
+
+				tarPath := writeBlobToFile(bp)
+				defer os.Remove(tarPath)
+
+				h.AssertOnTarEntry(t, tarPath,
+					"/cnb/buildpacks/bp.one/"+bp.Descriptor().Info.Version,
+					h.IsDirectory(),
+				)
+			}
+			h.AssertEq(t, versions, map[string]bool{"1.2.3": true, "4.5.6": true})
+		})
+
+		it("translates blob to distribution format using a custom buildpacks dir prefix", func() {
+			bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`))
+					return tarBuilder.Reader()
+				},
+			}, dist.WithBuildpacksDir("/cnb/experimental-buildpacks"))
+			h.AssertNil(t, err)
+
+			tarPath := writeBlobToFile(bp)
+			defer os.Remove(tarPath)
+
+			h.AssertOnTarEntry(t, tarPath,
+				"/cnb/experimental-buildpacks/bp.one/1.2.3",
+				h.IsDirectory(),
+				h.HasFileMode(0755),
+				h.HasModTime(archive.NormalizedDateTime),
+			)
+		})
+
+		it("allows normalizing file modes with a custom function", func() {
+			bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`))
+					tarBuilder.AddFile("some-file", 0600, time.Now(), []byte("some-data"))
+					return tarBuilder.Reader()
+				},
+			}, dist.WithFileModeFunc(func(*tar.Header) int64 {
+				return 0600
+			}))
+			h.AssertNil(t, err)
+
+			tarPath := writeBlobToFile(bp)
+			defer os.Remove(tarPath)
+
+			h.AssertOnTarEntry(t, tarPath,
+				"/cnb/buildpacks/bp.one/1.2.3/some-file",
+				h.HasFileMode(0600),
+			)
+		})
+
+		it("allows overriding the normalized timestamp", func() {
+			ts := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+			bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`))
+					tarBuilder.AddFile("some-file", 0600, time.Now(), []byte("some-data"))
+					return tarBuilder.Reader()
+				},
+			}, dist.WithNormalizedTimestamp(ts))
+			h.AssertNil(t, err)
+
+			tarPath := writeBlobToFile(bp)
+			defer os.Remove(tarPath)
+
+			h.AssertOnTarEntry(t, tarPath,
+				"/cnb/buildpacks/bp.one/1.2.3",
+				h.HasModTime(ts),
+			)
+
+			h.AssertOnTarEntry(t, tarPath,
+				"/cnb/buildpacks/bp.one/1.2.3/some-file",
+				h.HasModTime(ts),
+			)
+		})
+
+		it("preserves original mod times when requested", func() {
+			original := time.Date(2010, time.March, 4, 5, 6, 7, 0, time.UTC)
+			bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`))
+					tarBuilder.AddFile("some-file", 0600, original, []byte("some-data"))
 					return tarBuilder.Reader()
 				},
-			})
+			}, dist.WithNormalizedTimestamp(dist.PreserveModTime))
 			h.AssertNil(t, err)
 
 			tarPath := writeBlobToFile(bp)
 			defer os.Remove(tarPath)
 
 			h.AssertOnTarEntry(t, tarPath,
-				"/cnb/buildpacks/bp.one",
-				h.IsDirectory(),
-				h.HasFileMode(0755),
-				h.HasModTime(archive.NormalizedDateTime),
+				"/cnb/buildpacks/bp.one/1.2.3/some-file",
+				h.HasModTime(original),
 			)
+		})
 
-			h.AssertOnTarEntry(t, tarPath,
-				"/cnb/buildpacks/bp.one/1.2.3",
-				h.IsDirectory(),
-				h.HasFileMode(0755),
-				h.HasModTime(archive.NormalizedDateTime),
-			)
+		it("produces byte-for-byte identical tars regardless of source entry order", func() {
+			bpTOML := []byte(`
+api = "0.3"
 
-			h.AssertOnTarEntry(t, tarPath,
-				"/cnb/buildpacks/bp.one/1.2.3/bin",
-				h.IsDirectory(),
-				h.HasFileMode(0755),
-				h.HasModTime(archive.NormalizedDateTime),
-			)
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`)
+
+			buildWith := func(fileOrder []string) string {
+				bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+					openFn: func() io.ReadCloser {
+						tarBuilder := archive.TarBuilder{}
+						tarBuilder.AddFile("buildpack.toml", 0700, archive.NormalizedDateTime, bpTOML)
+						for _, name := range fileOrder {
+							tarBuilder.AddFile(name, 0644, archive.NormalizedDateTime, []byte(name+"-contents"))
+						}
+						return tarBuilder.Reader()
+					},
+				})
+				h.AssertNil(t, err)
+
+				return writeBlobToFile(bp)
+			}
+
+			firstPath := buildWith([]string{"a-file", "b-file", "c-file"})
+			defer os.Remove(firstPath)
+
+			secondPath := buildWith([]string{"c-file", "a-file", "b-file"})
+			defer os.Remove(secondPath)
+
+			firstBytes, err := ioutil.ReadFile(firstPath)
+			h.AssertNil(t, err)
+
+			secondBytes, err := ioutil.ReadFile(secondPath)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, digest.FromBytes(firstBytes), digest.FromBytes(secondBytes))
+		})
+
+		it("preserves symlinks", func() {
+			bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`))
+					tarBuilder.AddFile("bin/build", 0755, time.Now(), []byte("build-contents"))
+					tarBuilder.AddSymlink("bin/detect", "build", 0777, time.Now())
+					return tarBuilder.Reader()
+				},
+			})
+			h.AssertNil(t, err)
+
+			tarPath := writeBlobToFile(bp)
+			defer os.Remove(tarPath)
 
 			h.AssertOnTarEntry(t, tarPath,
 				"/cnb/buildpacks/bp.one/1.2.3/bin/detect",
-				h.HasFileMode(0755),
-				h.HasModTime(archive.NormalizedDateTime),
-				h.ContentEquals("detect-contents"),
+				h.SymlinksTo("build"),
 			)
+		})
 
-			h.AssertOnTarEntry(t, tarPath,
-				"/cnb/buildpacks/bp.one/1.2.3/bin/build",
-				h.HasFileMode(0755),
-				h.HasModTime(archive.NormalizedDateTime),
-				h.ContentEquals("build-contents"),
-			)
+		it("preserves PAX extended attributes, such as file capabilities", func() {
+			bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					buf := &bytes.Buffer{}
+					tw := tar.NewWriter(buf)
+
+					h.AssertNil(t, tw.WriteHeader(&tar.Header{
+						Name: "buildpack.toml",
+						Mode: 0644,
+						Size: int64(len(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`)),
+					}))
+					_, err := tw.Write([]byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`))
+					h.AssertNil(t, err)
+
+					capability := []byte("some-capability-bytes")
+					h.AssertNil(t, tw.WriteHeader(&tar.Header{
+						Name: "bin/build",
+						Mode: 0755,
+						Size: int64(len("build-contents")),
+						PAXRecords: map[string]string{
+							"SCHILY.xattr.security.capability": string(capability),
+						},
+					}))
+					_, err = tw.Write([]byte("build-contents"))
+					h.AssertNil(t, err)
+
+					h.AssertNil(t, tw.Close())
+					return ioutil.NopCloser(buf)
+				},
+			})
+			h.AssertNil(t, err)
+
+			bpReader, err := bp.Open()
+			h.AssertNil(t, err)
+
+			tr := tar.NewReader(bpReader)
+			for {
+				header, err := tr.Next()
+				h.AssertNil(t, err)
+
+				if header.Name == "/cnb/buildpacks/bp.one/1.2.3/bin/build" {
+					h.AssertEq(t, header.PAXRecords["SCHILY.xattr.security.capability"], "some-capability-bytes")
+					break
+				}
+			}
+		})
+
+		it("rejects entries that escape the buildpack's id/version root", func() {
+			bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+				openFn: func() io.ReadCloser {
+					tarBuilder := archive.TarBuilder{}
+					tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+api = "0.3"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+
+[[stacks]]
+id = "some.stack.id"
+`))
+					tarBuilder.AddFile("../../../etc/passwd", 0644, time.Now(), []byte("uh-oh"))
+					return tarBuilder.Reader()
+				},
+			})
+			h.AssertNil(t, err)
+
+			bpReader, err := bp.Open()
+			h.AssertNil(t, err)
+
+			_, err = io.Copy(ioutil.Discard, bpReader)
+			h.AssertError(t, err, "contains entry that escapes its root")
 		})
 
 		it("surfaces errors encountered while reading blob", func() {
@@ -365,7 +1017,7 @@ id = "some.stack.id"
 						return tarBuilder.Reader()
 					},
 				})
-				h.AssertError(t, err, "cannot have both 'stacks' and an 'order' defined")
+				h.AssertError(t, err, "cannot have more than one of 'stacks', 'targets', or an 'order' defined")
 			})
 		})
 
@@ -382,7 +1034,126 @@ version = "1.2.3"
 						return tarBuilder.Reader()
 					},
 				})
-				h.AssertError(t, err, "must have either 'stacks' or an 'order' defined")
+				h.AssertError(t, err, "must have either 'stacks', 'targets', or an 'order' defined")
+			})
+		})
+
+		when("there is an include directive", func() {
+			it("merges the included file's order groups into the descriptor", func() {
+				bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+					openFn: func() io.ReadCloser {
+						tarBuilder := archive.TarBuilder{}
+						tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+api = "0.3"
+include = "common-order.toml"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+`))
+						tarBuilder.AddFile("common-order.toml", 0700, time.Now(), []byte(`
+[[order]]
+[[order.group]]
+  id = "bp.nested"
+  version = "bp.nested.version"
+`))
+						return tarBuilder.Reader()
+					},
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(bp.Descriptor().Order), 1)
+				h.AssertEq(t, bp.Descriptor().Order[0].Group[0].ID, "bp.nested")
+			})
+
+			it("follows a chain of includes", func() {
+				bp, err := dist.BuildpackFromRootBlob(&readerBlob{
+					openFn: func() io.ReadCloser {
+						tarBuilder := archive.TarBuilder{}
+						tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+api = "0.3"
+include = "a.toml"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+`))
+						tarBuilder.AddFile("a.toml", 0700, time.Now(), []byte(`
+include = "b.toml"
+
+[[order]]
+[[order.group]]
+  id = "bp.a"
+  version = "1.0.0"
+`))
+						tarBuilder.AddFile("b.toml", 0700, time.Now(), []byte(`
+[[order]]
+[[order.group]]
+  id = "bp.b"
+  version = "1.0.0"
+`))
+						return tarBuilder.Reader()
+					},
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(bp.Descriptor().Order), 2)
+			})
+
+			it("returns a clear error when the include is missing", func() {
+				_, err := dist.BuildpackFromRootBlob(&readerBlob{
+					openFn: func() io.ReadCloser {
+						tarBuilder := archive.TarBuilder{}
+						tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+api = "0.3"
+include = "missing.toml"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+`))
+						return tarBuilder.Reader()
+					},
+				})
+				h.AssertError(t, err, "include 'missing.toml': no such file")
+			})
+
+			it("returns a clear error on an include cycle", func() {
+				_, err := dist.BuildpackFromRootBlob(&readerBlob{
+					openFn: func() io.ReadCloser {
+						tarBuilder := archive.TarBuilder{}
+						tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+api = "0.3"
+include = "a.toml"
+
+[buildpack]
+id = "bp.one"
+version = "1.2.3"
+`))
+						tarBuilder.AddFile("a.toml", 0700, time.Now(), []byte(`
+include = "buildpack.toml"
+`))
+						return tarBuilder.Reader()
+					},
+				})
+				h.AssertError(t, err, "cycle detected while resolving 'a.toml'")
+			})
+		})
+
+		when("WithLaxParsing", func() {
+			it("collects every descriptor problem instead of stopping at the first", func() {
+				_, err := dist.BuildpackFromRootBlob(&readerBlob{
+					openFn: func() io.ReadCloser {
+						tarBuilder := archive.TarBuilder{}
+						tarBuilder.AddFile("buildpack.toml", 0700, time.Now(), []byte(`
+[buildpack]
+id = ""
+version = ""
+`))
+						return tarBuilder.Reader()
+					},
+				}, dist.WithLaxParsing())
+				h.AssertError(t, err, "'buildpack.id' is required")
+				h.AssertError(t, err, "'buildpack.version' is required")
+				h.AssertError(t, err, "must have either 'stacks', 'targets', or an 'order' defined")
 			})
 		})
 	})
@@ -432,3 +1203,69 @@ type readerBlob struct {
 func (r *readerBlob) Open() (io.ReadCloser, error) {
 	return r.openFn(), nil
 }
+
+// buildOCILayoutBlob constructs a tar-formatted reader whose contents are laid out as an
+// exploded OCI image layout, with descriptorContents stored as "buildpack.toml" inside the
+// single layer referenced by the image manifest.
+func buildOCILayoutBlob(t *testing.T, descriptorContents []byte) io.ReadCloser {
+	t.Helper()
+	return buildOCILayoutBlobs(t, [][]byte{descriptorContents})
+}
+
+// buildOCILayoutBlobs constructs a tar-formatted reader laid out as an exploded OCI image
+// layout with one layer (and one "buildpack.toml") per entry in descriptorContentsByLayer.
+func buildOCILayoutBlobs(t *testing.T, descriptorContentsByLayer [][]byte) io.ReadCloser {
+	t.Helper()
+
+	configBytes := []byte("{}")
+	configDigest := digest.FromBytes(configBytes)
+
+	var layerDescriptors []v1.Descriptor
+	blobs := map[string][]byte{
+		configDigest.Algorithm().String() + "/" + configDigest.Encoded(): configBytes,
+	}
+	for _, descriptorContents := range descriptorContentsByLayer {
+		var layerBuf bytes.Buffer
+		gzw := gzip.NewWriter(&layerBuf)
+		layerTarBuilder := archive.TarBuilder{}
+		layerTarBuilder.AddFile("buildpack.toml", 0644, time.Now(), descriptorContents)
+		_, err := layerTarBuilder.WriteTo(gzw)
+		h.AssertNil(t, err)
+		h.AssertNil(t, gzw.Close())
+		layerBytes := layerBuf.Bytes()
+		layerDigest := digest.FromBytes(layerBytes)
+
+		blobs[layerDigest.Algorithm().String()+"/"+layerDigest.Encoded()] = layerBytes
+		layerDescriptors = append(layerDescriptors, v1.Descriptor{
+			MediaType: "application/vnd.oci.image.layer.v1.tar+gzip",
+			Digest:    layerDigest,
+			Size:      int64(len(layerBytes)),
+		})
+	}
+
+	manifest := v1.Manifest{
+		Config: v1.Descriptor{MediaType: "application/vnd.oci.image.config.v1+json", Digest: configDigest, Size: int64(len(configBytes))},
+		Layers: layerDescriptors,
+	}
+	manifestBytes, err := json.Marshal(manifest)
+	h.AssertNil(t, err)
+	manifestDigest := digest.FromBytes(manifestBytes)
+	blobs[manifestDigest.Algorithm().String()+"/"+manifestDigest.Encoded()] = manifestBytes
+
+	index := v1.Index{
+		Manifests: []v1.Descriptor{
+			{MediaType: "application/vnd.oci.image.manifest.v1+json", Digest: manifestDigest, Size: int64(len(manifestBytes))},
+		},
+	}
+	indexBytes, err := json.Marshal(index)
+	h.AssertNil(t, err)
+
+	tarBuilder := archive.TarBuilder{}
+	tarBuilder.AddFile(v1.ImageLayoutFile, 0644, time.Now(), []byte(`{"imageLayoutVersion":"1.0.0"}`))
+	tarBuilder.AddFile("index.json", 0644, time.Now(), indexBytes)
+	for name, contents := range blobs {
+		tarBuilder.AddFile("blobs/"+name, 0644, time.Now(), contents)
+	}
+
+	return tarBuilder.Reader()
+}
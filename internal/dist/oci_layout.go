@@ -0,0 +1,215 @@
+package dist
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path"
+
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/archive"
+)
+
+// readBuildpackDescriptorBlob reads the contents of "buildpack.toml" from a blob, along with every
+// other entry in the blob (indexed by cleaned path), so a caller can resolve sibling files -- such
+// as an "include" directive -- relative to the blob root. If the blob contents are a flat
+// buildpack directory, the descriptor is read directly from its root. If the blob is instead an
+// exploded OCI layout (marked by an "oci-layout" file at its root), the descriptor is located
+// inside the layer that contains it. The blob's tar contents may optionally be gzip-compressed.
+func readBuildpackDescriptorBlob(rc io.Reader) ([]byte, map[string][]byte, error) {
+	entries, err := readOCILayoutBlob(rc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if buf, ok := entries["buildpack.toml"]; ok {
+		return buf, entries, nil
+	}
+
+	buf, found, err := findOCILayoutBuildpackDescriptor(entries)
+	if err != nil {
+		return nil, nil, err
+	}
+	if found {
+		return buf, entries, nil
+	}
+
+	return nil, nil, errors.Wrapf(archive.ErrEntryNotExist, "could not find entry path '%s'", "buildpack.toml")
+}
+
+// readOCILayoutBlob reads the entries of a tar-formatted blob into memory, returning
+// the contents indexed by cleaned entry path. This allows callers to look up the
+// "oci-layout" marker file, "index.json", and any referenced "blobs/sha256/<digest>"
+// entries without needing to re-open the blob for each lookup. The blob's contents may
+// optionally be gzip-compressed; this is detected automatically.
+func readOCILayoutBlob(rc io.Reader) (map[string][]byte, error) {
+	entries := map[string][]byte{}
+
+	r, err := maybeDecompress(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get next tar entry")
+		}
+
+		buf, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read contents of '%s'", header.Name)
+		}
+
+		entries[path.Clean(header.Name)] = buf
+	}
+
+	return entries, nil
+}
+
+// findOCILayoutBuildpackDescriptor looks for an "oci-layout" marker file at the blob root and,
+// if present, follows "index.json" to the image manifest and returns the contents of
+// "buildpack.toml" found within the first layer that contains it.
+func findOCILayoutBuildpackDescriptor(entries map[string][]byte) (buf []byte, ok bool, err error) {
+	if _, present := entries[v1.ImageLayoutFile]; !present {
+		return nil, false, nil
+	}
+
+	descriptors, err := enumerateOCILayoutDescriptors(entries)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(descriptors) == 0 {
+		return nil, false, errors.New("could not find entry path 'buildpack.toml' in any oci layout layer")
+	}
+
+	return descriptors[0].descriptorBytes, true, nil
+}
+
+func readOCILayoutBlobByDigest(entries map[string][]byte, digest string) ([]byte, error) {
+	algoAndHex := digestToPath(digest)
+	buf, ok := entries[path.Join("blobs", algoAndHex)]
+	if !ok {
+		return nil, errors.Errorf("could not find blob for digest '%s'", digest)
+	}
+	return buf, nil
+}
+
+func digestToPath(digest string) string {
+	for i, c := range digest {
+		if c == ':' {
+			return path.Join(digest[:i], digest[i+1:])
+		}
+	}
+	return digest
+}
+
+// gzipMagic is the two-byte magic number identifying a gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// maybeDecompress peeks at the first two bytes of r and transparently wraps it in a
+// gzip.Reader if they match the gzip magic number, leaving uncompressed tar streams
+// untouched.
+func maybeDecompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	peeked, err := br.Peek(len(gzipMagic))
+	if err != nil {
+		// Fewer bytes than the gzip magic number means this can't be a gzip stream;
+		// let the tar reader surface any resulting error.
+		return br, nil
+	}
+
+	if bytes.Equal(peeked, gzipMagic) {
+		gzr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating gzip reader for blob")
+		}
+		return gzr, nil
+	}
+
+	return br, nil
+}
+
+func gunzip(b []byte) ([]byte, error) {
+	gzr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating gzip reader for layer")
+	}
+	defer gzr.Close()
+
+	buf, err := ioutil.ReadAll(gzr)
+	if err != nil {
+		return nil, errors.Wrap(err, "decompressing layer")
+	}
+	return buf, nil
+}
+
+// ociLayoutDescriptor pairs a layer's decompressed tar contents with the buildpack.toml
+// contents found inside it.
+type ociLayoutDescriptor struct {
+	tarBytes        []byte
+	descriptorBytes []byte
+}
+
+// enumerateOCILayoutDescriptors walks every manifest and layer referenced by an OCI layout's
+// "index.json", returning one ociLayoutDescriptor for each layer that contains a "buildpack.toml"
+// at its root.
+func enumerateOCILayoutDescriptors(entries map[string][]byte) ([]ociLayoutDescriptor, error) {
+	indexBytes, ok := entries["index.json"]
+	if !ok {
+		return nil, errors.New("oci layout is missing 'index.json'")
+	}
+
+	var index v1.Index
+	if err := json.Unmarshal(indexBytes, &index); err != nil {
+		return nil, errors.Wrap(err, "parsing 'index.json'")
+	}
+
+	var found []ociLayoutDescriptor
+	for _, manifestDesc := range index.Manifests {
+		manifestBytes, err := readOCILayoutBlobByDigest(entries, manifestDesc.Digest.String())
+		if err != nil {
+			return nil, errors.Wrap(err, "reading oci layout manifest")
+		}
+
+		var manifest v1.Manifest
+		if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+			return nil, errors.Wrap(err, "parsing oci layout manifest")
+		}
+
+		for _, layer := range manifest.Layers {
+			layerBytes, err := readOCILayoutBlobByDigest(entries, layer.Digest.String())
+			if err != nil {
+				return nil, errors.Wrapf(err, "reading oci layout layer '%s'", layer.Digest.String())
+			}
+
+			tarBytes, err := gunzip(layerBytes)
+			if err != nil {
+				return nil, err
+			}
+
+			_, descriptorBytes, err := archive.ReadTarEntry(bytes.NewReader(tarBytes), "buildpack.toml")
+			if archive.IsEntryNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			found = append(found, ociLayoutDescriptor{tarBytes: tarBytes, descriptorBytes: descriptorBytes})
+		}
+	}
+
+	return found, nil
+}
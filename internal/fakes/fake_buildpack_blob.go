@@ -2,47 +2,41 @@ package fakes
 
 import (
 	"bytes"
-	"io"
+	"io/ioutil"
 	"time"
 
 	"github.com/BurntSushi/toml"
 
 	"github.com/buildpacks/pack/internal/archive"
+	"github.com/buildpacks/pack/internal/blob"
 	"github.com/buildpacks/pack/internal/dist"
 )
 
-type fakeBuildpackBlob struct {
-	descriptor dist.BuildpackDescriptor
-	chmod      int64
-}
-
 // NewFakeBuildpackBlob creates a fake blob with contents:
 //
-// 	\_ buildpack.toml
-// 	\_ bin
-// 	\_ bin/build
-//  	build-contents
-// 	\_ bin/detect
-//  	detect-contents
-func NewFakeBuildpackBlob(descriptor dist.BuildpackDescriptor, chmod int64) (dist.Blob, error) {
-	return &fakeBuildpackBlob{
-		descriptor: descriptor,
-		chmod:      chmod,
-	}, nil
-}
-
-func (b *fakeBuildpackBlob) Open() (reader io.ReadCloser, err error) {
+//		\_ buildpack.toml
+//		\_ bin
+//		\_ bin/build
+//	 	build-contents
+//		\_ bin/detect
+//	 	detect-contents
+func NewFakeBuildpackBlob(descriptor dist.BuildpackDescriptor, chmod int64) (blob.Blob, error) {
 	buf := &bytes.Buffer{}
-	if err = toml.NewEncoder(buf).Encode(b.descriptor); err != nil {
+	if err := toml.NewEncoder(buf).Encode(descriptor); err != nil {
 		return nil, err
 	}
 
 	tarBuilder := archive.TarBuilder{}
 
-	tarBuilder.AddFile("buildpack.toml", b.chmod, time.Now(), buf.Bytes())
-	tarBuilder.AddDir("bin", b.chmod, time.Now())
-	tarBuilder.AddFile("bin/build", b.chmod, time.Now(), []byte("build-contents"))
-	tarBuilder.AddFile("bin/detect", b.chmod, time.Now(), []byte("detect-contents"))
+	tarBuilder.AddFile("buildpack.toml", chmod, time.Now(), buf.Bytes())
+	tarBuilder.AddDir("bin", chmod, time.Now())
+	tarBuilder.AddFile("bin/build", chmod, time.Now(), []byte("build-contents"))
+	tarBuilder.AddFile("bin/detect", chmod, time.Now(), []byte("detect-contents"))
+
+	data, err := ioutil.ReadAll(tarBuilder.Reader())
+	if err != nil {
+		return nil, err
+	}
 
-	return tarBuilder.Reader(), err
+	return blob.NewBlobFromBytes(data), nil
 }
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/buildpacks/imgutil"
 	"github.com/buildpacks/imgutil/local"
@@ -92,7 +93,15 @@ func (f *Fetcher) pullImage(ctx context.Context, imageID string) error {
 	writer := logging.GetWriterForLevel(f.logger, logging.InfoLevel)
 	termFd, isTerm := isTerminal(writer)
 
-	err = jsonmessage.DisplayJSONMessagesStream(rc, &colorizedWriter{writer}, termFd, isTerm, nil)
+	out := io.Writer(&colorizedWriter{writer})
+	if !isTerm {
+		// A terminal redraws progress in place, so docker's own per-event lines never
+		// accumulate. Piped output has no such redraw, so throttle it here instead --
+		// otherwise a multi-layer pull floods the log with one line per progress tick.
+		out = newThrottledWriter(out, progressThrottleInterval)
+	}
+
+	err = jsonmessage.DisplayJSONMessagesStream(rc, out, termFd, isTerm, nil)
 	if err != nil {
 		return err
 	}
@@ -156,3 +165,37 @@ func (w *colorizedWriter) Write(p []byte) (n int, err error) {
 	}
 	return w.writer.Write([]byte(msg))
 }
+
+// progressThrottleInterval bounds how often a throttledWriter lets a progress update through.
+const progressThrottleInterval = 100 * time.Millisecond
+
+// throttledWriter drops writes that arrive faster than interval apart, so a pull's many layer
+// progress ticks don't flood a non-terminal log with one line per tick. Writes that look like a
+// layer's final status (e.g. "Pull complete") always go through immediately, so the log still
+// shows every layer finishing even if its in-progress ticks were dropped.
+type throttledWriter struct {
+	writer   io.Writer
+	interval time.Duration
+	lastSent time.Time
+}
+
+func newThrottledWriter(writer io.Writer, interval time.Duration) *throttledWriter {
+	return &throttledWriter{writer: writer, interval: interval}
+}
+
+func (w *throttledWriter) Write(p []byte) (int, error) {
+	if !w.isFinal(p) && time.Since(w.lastSent) < w.interval {
+		return len(p), nil
+	}
+	w.lastSent = time.Now()
+	return w.writer.Write(p)
+}
+
+func (w *throttledWriter) isFinal(p []byte) bool {
+	for _, status := range []string{"Pull complete", "Download complete", "Already exists"} {
+		if strings.Contains(string(p), status) {
+			return true
+		}
+	}
+	return false
+}
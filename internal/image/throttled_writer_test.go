@@ -0,0 +1,67 @@
+package image
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestThrottledWriter(t *testing.T) {
+	t.Run("lets the first write through", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := newThrottledWriter(&buf, time.Hour)
+
+		if _, err := w.Write([]byte("Downloading [====>    ] 10%\n")); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if buf.String() != "Downloading [====>    ] 10%\n" {
+			t.Fatalf("unexpected output: %q", buf.String())
+		}
+	})
+
+	t.Run("drops a progress write that arrives before the interval elapses", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := newThrottledWriter(&buf, time.Hour)
+
+		if _, err := w.Write([]byte("Downloading [====>    ] 10%\n")); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if _, err := w.Write([]byte("Downloading [======>  ] 20%\n")); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if buf.String() != "Downloading [====>    ] 10%\n" {
+			t.Fatalf("unexpected output: %q", buf.String())
+		}
+	})
+
+	t.Run("always lets a final status through, even before the interval elapses", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := newThrottledWriter(&buf, time.Hour)
+
+		if _, err := w.Write([]byte("Downloading [====>    ] 10%\n")); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if _, err := w.Write([]byte("Pull complete\n")); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if buf.String() != "Downloading [====>    ] 10%\nPull complete\n" {
+			t.Fatalf("unexpected output: %q", buf.String())
+		}
+	})
+
+	t.Run("lets a write through once the interval has elapsed", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := newThrottledWriter(&buf, time.Millisecond)
+
+		if _, err := w.Write([]byte("Downloading [====>    ] 10%\n")); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+		if _, err := w.Write([]byte("Downloading [======>  ] 20%\n")); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if buf.String() != "Downloading [====>    ] 10%\nDownloading [======>  ] 20%\n" {
+			t.Fatalf("unexpected output: %q", buf.String())
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"io"
+	"os"
+
+	"github.com/heroku/color"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// LoggerOption configures a Logger created by NewLogger.
+type LoggerOption func(*loggerOptions)
+
+type loggerOptions struct {
+	json bool
+}
+
+// WithJSON configures NewLogger to return a JSON logger (see NewJSON) instead of the default
+// text logger.
+func WithJSON() LoggerOption {
+	return func(o *loggerOptions) {
+		o.json = true
+	}
+}
+
+// NewLogger creates a Logger for the pack library the same way New and NewJSON do, but first
+// disables package style's colorized output -- globally, via color.Disable -- when the NO_COLOR
+// environment variable is set or w isn't a terminal, per the convention described at
+// https://no-color.org. It never re-enables color that's already been disabled some other way,
+// e.g. by an explicit --no-color flag.
+func NewLogger(w io.Writer, opts ...LoggerOption) Logger {
+	if _, noColorSet := os.LookupEnv("NO_COLOR"); noColorSet || !isTerminal(w) {
+		color.Disable(true)
+	}
+
+	var o loggerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.json {
+		return NewJSON(w)
+	}
+	return New(w)
+}
+
+// fdWriter is implemented by *os.File and other writers that can report the file descriptor
+// they write to.
+type fdWriter interface {
+	Fd() uintptr
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(fdWriter)
+	if !ok {
+		return false
+	}
+	return terminal.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/heroku/color"
+)
+
+func TestNewLogger(t *testing.T) {
+	t.Run("NO_COLOR is set", func(t *testing.T) {
+		color.Disable(false)
+		defer color.Disable(false)
+
+		os.Setenv("NO_COLOR", "1")
+		defer os.Unsetenv("NO_COLOR")
+
+		NewLogger(&bytes.Buffer{})
+
+		if color.Enabled() {
+			t.Fatalf("expected NewLogger to disable color when NO_COLOR is set")
+		}
+	})
+
+	t.Run("writer is not a terminal", func(t *testing.T) {
+		color.Disable(false)
+		defer color.Disable(false)
+
+		NewLogger(&bytes.Buffer{})
+
+		if color.Enabled() {
+			t.Fatalf("expected NewLogger to disable color for a non-terminal writer")
+		}
+	})
+
+	t.Run("WithJSON returns a JSON logger", func(t *testing.T) {
+		var w bytes.Buffer
+		logger := NewLogger(&w, WithJSON())
+
+		logger.Info("test")
+
+		h := `{"level":"info","message":"test"}` + "\n"
+		if w.String() != h {
+			t.Fatalf("expected %q, got %q", h, w.String())
+		}
+	})
+}
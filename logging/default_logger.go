@@ -9,12 +9,14 @@ import (
 // New creates a default logger for the pack library. Note that the pack CLI has it's own logger.
 func New(w io.Writer) Logger {
 	return &defaultLogger{
-		out: log.New(w, "", log.LstdFlags|log.Lmicroseconds),
+		out:   log.New(w, "", log.LstdFlags|log.Lmicroseconds),
+		level: InfoLevel,
 	}
 }
 
 type defaultLogger struct {
-	out *log.Logger
+	out   *log.Logger
+	level Level
 }
 
 const (
@@ -25,35 +27,64 @@ const (
 	prefixFmt   = "%-7s %s"
 )
 
+// SetLevel filters out messages below the given Level. The default level is InfoLevel.
+func (l *defaultLogger) SetLevel(level Level) {
+	l.level = level
+}
+
 func (l *defaultLogger) Debug(msg string) {
+	if l.level > DebugLevel {
+		return
+	}
 	l.out.Printf(prefixFmt, debugPrefix, msg)
 }
 
 func (l *defaultLogger) Debugf(format string, v ...interface{}) {
+	if l.level > DebugLevel {
+		return
+	}
 	l.out.Printf(prefixFmt, debugPrefix, fmt.Sprintf(format, v...))
 }
 
 func (l *defaultLogger) Info(msg string) {
+	if l.level > InfoLevel {
+		return
+	}
 	l.out.Printf(prefixFmt, infoPrefix, msg)
 }
 
 func (l *defaultLogger) Infof(format string, v ...interface{}) {
+	if l.level > InfoLevel {
+		return
+	}
 	l.out.Printf(prefixFmt, infoPrefix, fmt.Sprintf(format, v...))
 }
 
 func (l *defaultLogger) Warn(msg string) {
+	if l.level > WarnLevel {
+		return
+	}
 	l.out.Printf(prefixFmt, warnPrefix, msg)
 }
 
 func (l *defaultLogger) Warnf(format string, v ...interface{}) {
+	if l.level > WarnLevel {
+		return
+	}
 	l.out.Printf(prefixFmt, warnPrefix, fmt.Sprintf(format, v...))
 }
 
 func (l *defaultLogger) Error(msg string) {
+	if l.level > ErrorLevel {
+		return
+	}
 	l.out.Printf(prefixFmt, errorPrefix, msg)
 }
 
 func (l *defaultLogger) Errorf(format string, v ...interface{}) {
+	if l.level > ErrorLevel {
+		return
+	}
 	l.out.Printf(prefixFmt, errorPrefix, fmt.Sprintf(format, v...))
 }
 
@@ -62,5 +93,5 @@ func (l *defaultLogger) Writer() io.Writer {
 }
 
 func (l *defaultLogger) IsVerbose() bool {
-	return false
+	return l.level <= DebugLevel
 }
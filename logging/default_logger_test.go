@@ -29,11 +29,13 @@ func TestDefaultLogger(t *testing.T) {
 		})
 
 		it("should print debug messages properly", func() {
+			logger.(WithLevel).SetLevel(DebugLevel)
 			logger.Debug("test")
 			h.AssertMatch(t, w.String(), debugMatcher)
 		})
 
 		it("should format debug messages properly", func() {
+			logger.(WithLevel).SetLevel(DebugLevel)
 			logger.Debugf("test%s", "foo")
 			h.AssertMatch(t, w.String(), debugMatcher)
 		})
@@ -62,5 +64,22 @@ func TestDefaultLogger(t *testing.T) {
 			_, _ = logger.Writer().Write([]byte("test"))
 			h.AssertEq(t, w.String(), "test")
 		})
+
+		it("should suppress debug messages by default", func() {
+			logger.Debug("test")
+			h.AssertEq(t, w.String(), "")
+		})
+
+		it("should print debug messages once verbose is enabled via SetLevel", func() {
+			logger.(WithLevel).SetLevel(DebugLevel)
+			logger.Debug("test")
+			h.AssertMatch(t, w.String(), debugMatcher)
+		})
+
+		it("should suppress info messages once SetLevel raises the threshold", func() {
+			logger.(WithLevel).SetLevel(WarnLevel)
+			logger.Info("test")
+			h.AssertEq(t, w.String(), "")
+		})
 	})
 }
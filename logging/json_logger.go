@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// NewJSON creates a logger for the pack library that emits newline-delimited JSON objects
+// instead of human-styled text, for consumption by structured log stores.
+func NewJSON(w io.Writer) Logger {
+	return &jsonLogger{out: w, level: InfoLevel}
+}
+
+type jsonLogger struct {
+	out   io.Writer
+	level Level
+}
+
+// SetLevel filters out messages below the given Level. The default level is InfoLevel.
+func (l *jsonLogger) SetLevel(level Level) {
+	l.level = level
+}
+
+type jsonEvent struct {
+	Phase   string `json:"phase,omitempty"`
+	Level   string `json:"level"`
+	Message string `json:"message,omitempty"`
+}
+
+func (l *jsonLogger) emit(e jsonEvent) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(l.out, string(b))
+}
+
+// PhaseStart emits a {"phase":"<lowercased phase>","level":"info"} event.
+func (l *jsonLogger) PhaseStart(phase string) {
+	if l.level > InfoLevel {
+		return
+	}
+	l.emit(jsonEvent{Phase: strings.ToLower(phase), Level: "info"})
+}
+
+func (l *jsonLogger) Debug(msg string) {
+	if l.level > DebugLevel {
+		return
+	}
+	l.emit(jsonEvent{Level: "debug", Message: msg})
+}
+
+func (l *jsonLogger) Debugf(format string, v ...interface{}) {
+	l.Debug(fmt.Sprintf(format, v...))
+}
+
+func (l *jsonLogger) Info(msg string) {
+	if l.level > InfoLevel {
+		return
+	}
+	l.emit(jsonEvent{Level: "info", Message: msg})
+}
+
+func (l *jsonLogger) Infof(format string, v ...interface{}) {
+	l.Info(fmt.Sprintf(format, v...))
+}
+
+func (l *jsonLogger) Warn(msg string) {
+	if l.level > WarnLevel {
+		return
+	}
+	l.emit(jsonEvent{Level: "warn", Message: msg})
+}
+
+func (l *jsonLogger) Warnf(format string, v ...interface{}) {
+	l.Warn(fmt.Sprintf(format, v...))
+}
+
+func (l *jsonLogger) Error(msg string) {
+	if l.level > ErrorLevel {
+		return
+	}
+	l.emit(jsonEvent{Level: "error", Message: msg})
+}
+
+func (l *jsonLogger) Errorf(format string, v ...interface{}) {
+	l.Error(fmt.Sprintf(format, v...))
+}
+
+func (l *jsonLogger) Writer() io.Writer {
+	return l.out
+}
+
+func (l *jsonLogger) IsVerbose() bool {
+	return l.level <= DebugLevel
+}
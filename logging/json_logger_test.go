@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sclevine/spec"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestJSONLogger(t *testing.T) {
+	spec.Run(t, "JSONLogger", func(t *testing.T, when spec.G, it spec.S) {
+		var w bytes.Buffer
+		var logger Logger
+
+		it.Before(func() {
+			logger = NewJSON(&w)
+		})
+
+		it.After(func() {
+			w.Reset()
+		})
+
+		it("should print debug messages as JSON", func() {
+			logger.(WithLevel).SetLevel(DebugLevel)
+			logger.Debug("test")
+			h.AssertEq(t, w.String(), `{"level":"debug","message":"test"}`+"\n")
+		})
+
+		it("should format debug messages as JSON", func() {
+			logger.(WithLevel).SetLevel(DebugLevel)
+			logger.Debugf("test%s", "foo")
+			h.AssertEq(t, w.String(), `{"level":"debug","message":"testfoo"}`+"\n")
+		})
+
+		it("should print info messages as JSON", func() {
+			logger.Info("test")
+			h.AssertEq(t, w.String(), `{"level":"info","message":"test"}`+"\n")
+		})
+
+		it("should print warn messages as JSON", func() {
+			logger.Warn("test")
+			h.AssertEq(t, w.String(), `{"level":"warn","message":"test"}`+"\n")
+		})
+
+		it("should print error messages as JSON", func() {
+			logger.Error("test")
+			h.AssertEq(t, w.String(), `{"level":"error","message":"test"}`+"\n")
+		})
+
+		it("should emit a phase event when the logger supports it", func() {
+			PhaseStart(logger, "PREPARING")
+			h.AssertEq(t, w.String(), `{"phase":"preparing","level":"info"}`+"\n")
+		})
+
+		it("should not format writer messages", func() {
+			_, _ = logger.Writer().Write([]byte("test"))
+			h.AssertEq(t, w.String(), "test")
+		})
+
+		it("should suppress debug messages by default", func() {
+			logger.Debug("test")
+			h.AssertEq(t, w.String(), "")
+		})
+
+		it("should print debug messages once verbose is enabled via SetLevel", func() {
+			logger.(WithLevel).SetLevel(DebugLevel)
+			logger.Debug("test")
+			h.AssertEq(t, w.String(), `{"level":"debug","message":"test"}`+"\n")
+		})
+
+		it("should suppress info messages once SetLevel raises the threshold", func() {
+			logger.(WithLevel).SetLevel(WarnLevel)
+			logger.Info("test")
+			h.AssertEq(t, w.String(), "")
+		})
+	})
+}
+
+func TestPhaseStart(t *testing.T) {
+	spec.Run(t, "PhaseStart", func(t *testing.T, when spec.G, it spec.S) {
+		it("falls back to a styled Info message when the logger has no PhaseStart method", func() {
+			var w bytes.Buffer
+			logger := New(&w)
+
+			PhaseStart(logger, "PREPARING")
+
+			h.AssertContains(t, w.String(), "PREPARING")
+		})
+	})
+}
@@ -41,6 +41,32 @@ type WithSelectableWriter interface {
 	WriterForLevel(level Level) io.Writer
 }
 
+// WithLevel is an optional interface for loggers that support filtering out messages below a
+// minimum Level. The default level, when never set, is InfoLevel.
+type WithLevel interface {
+	SetLevel(level Level)
+}
+
+// WithPhaseStart is an optional interface for loggers that want to emit a distinct event when a
+// lifecycle phase starts, rather than having the phase name formatted into an Info message.
+type WithPhaseStart interface {
+	PhaseStart(phase string)
+}
+
+// PhaseStart announces the start of a lifecycle phase. If logger implements WithPhaseStart, its
+// PhaseStart method handles the announcement; otherwise it falls back to logging the same styled
+// step text pack has always used.
+//
+// See WithPhaseStart
+func PhaseStart(logger Logger, phase string) {
+	if pl, ok := logger.(WithPhaseStart); ok {
+		pl.PhaseStart(phase)
+		return
+	}
+
+	logger.Info(style.Step(phase))
+}
+
 // GetWriterForLevel retrieves the appropriate Writer for the log level provided.
 //
 // See WithSelectableWriter
@@ -41,7 +41,7 @@ func (c *Client) PackageBuildpack(ctx context.Context, opts PackageBuildpackOpti
 		return errors.Wrapf(err, "downloading buildpack from %s", style.Symbol(bpURI))
 	}
 
-	bp, err := dist.BuildpackFromRootBlob(blob)
+	bp, err := dist.BuildpackFromRootBlob(blob, dist.WithLogger(c.logger))
 	if err != nil {
 		return errors.Wrapf(err, "creating buildpack from %s", style.Symbol(bpURI))
 	}
@@ -55,7 +55,7 @@ func (c *Client) PackageBuildpack(ctx context.Context, opts PackageBuildpackOpti
 				return errors.Wrapf(err, "downloading buildpack from %s", style.Symbol(dep.URI))
 			}
 
-			depBP, err := dist.BuildpackFromRootBlob(blob)
+			depBP, err := dist.BuildpackFromRootBlob(blob, dist.WithLogger(c.logger))
 			if err != nil {
 				return errors.Wrapf(err, "creating buildpack from %s", style.Symbol(dep.URI))
 			}
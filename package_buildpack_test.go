@@ -228,6 +228,16 @@ func testPackageBuildpack(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("no buildpack URI is provided", func() {
+		it("should error", func() {
+			err := subject.PackageBuildpack(context.TODO(), pack.PackageBuildpackOptions{
+				Name:   "some-buildpack",
+				Config: pubbldpkg.Config{},
+			})
+			h.AssertError(t, err, "buildpack URI must be provided")
+		})
+	})
+
 	when("unknown format is provided", func() {
 		it("should error", func() {
 			err := subject.PackageBuildpack(context.TODO(), pack.PackageBuildpackOptions{